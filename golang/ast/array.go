@@ -14,6 +14,8 @@ var _ Expression = (*ArrayLiteral)(nil)
 type ArrayLiteral struct {
 	Token    token.Token
 	Elements []Expression
+	// End is the position just past the closing bracket.
+	End token.Position
 }
 
 // String implements Expression.
@@ -39,3 +41,8 @@ func (a *ArrayLiteral) TokenLiteral() string {
 // expressionNode implements Expression.
 func (a *ArrayLiteral) expressionNode() {
 }
+
+// Span implements Node.
+func (a *ArrayLiteral) Span() token.Span {
+	return token.Span{Start: a.Token.Start, End: a.End}
+}
@@ -0,0 +1,46 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"bytes"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+var _ Expression = (*AssignExpression)(nil)
+
+// AssignExpression represents an assignment such as x = 1, x += 1 or
+// a[i] = v in the AST. Target is an *Identifier or an *IndexExpression.
+type AssignExpression struct {
+	Token    token.Token // the assignment operator
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+// String implements Expression.
+func (a *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(a.Target.String())
+	out.WriteString(" " + a.Operator + " ")
+	out.WriteString(a.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (a *AssignExpression) TokenLiteral() string {
+	return a.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (a *AssignExpression) expressionNode() {
+}
+
+// Span implements Node.
+func (a *AssignExpression) Span() token.Span {
+	return joinSpan(a.Token, a.Target, a.Value)
+}
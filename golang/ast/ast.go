@@ -1,10 +1,14 @@
 // Package ast defines the abstract syntax tree for the Monkey programming language.
 package ast
 
+import "github.com/w40141/monkey-language/golang/token"
+
 // Node is the interface that all nodes in the AST implement.
 type Node interface {
 	TokenLiteral() string
 	String() string
+	// Span returns the source range covered by the node.
+	Span() token.Span
 }
 
 // Statement is the interface that all statement nodes in the AST implement.
@@ -18,3 +22,24 @@ type Expression interface {
 	Node
 	expressionNode()
 }
+
+// spanOf returns the span of the node, or the zero span if the node is missing.
+func spanOf(n Node) token.Span {
+	if n == nil {
+		return token.Span{}
+	}
+	return n.Span()
+}
+
+// joinSpan returns the span from the start of first to the end of last.
+// Missing ends fall back to the start token.
+func joinSpan(tok token.Token, first, last Node) token.Span {
+	span := tok.Span()
+	if s := spanOf(first); s.Start.IsValid() {
+		span.Start = s.Start
+	}
+	if s := spanOf(last); s.End.IsValid() {
+		span.End = s.End
+	}
+	return span
+}
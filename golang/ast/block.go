@@ -11,8 +11,10 @@ var _ Expression = (*BlockStatement)(nil)
 
 // BlockStatement represents a block statement in the AST.
 type BlockStatement struct {
-	Token     token.Token
+	Token      token.Token
 	Statements []Statement
+	// End is the position just past the closing brace.
+	End token.Position
 }
 
 // String implements Expression.
@@ -33,3 +35,8 @@ func (b *BlockStatement) TokenLiteral() string {
 
 // expressionNode implements Expression.
 func (b *BlockStatement) expressionNode() {}
+
+// Span implements Node.
+func (b *BlockStatement) Span() token.Span {
+	return token.Span{Start: b.Token.Start, End: b.End}
+}
@@ -23,3 +23,8 @@ func (b *Boolean) TokenLiteral() string {
 
 // expressionNode implements Expression.
 func (b *Boolean) expressionNode() {}
+
+// Span implements Node.
+func (b *Boolean) Span() token.Span {
+	return b.Token.Span()
+}
@@ -15,6 +15,8 @@ type CallExpression struct {
 	Token     token.Token
 	Function  Expression
 	Arguments []Expression
+	// End is the position just past the closing parenthesis.
+	End token.Position
 }
 
 // String implements Expression.
@@ -42,3 +44,10 @@ func (c *CallExpression) TokenLiteral() string {
 // expressionNode implements Expression.
 func (c *CallExpression) expressionNode() {
 }
+
+// Span implements Node.
+func (c *CallExpression) Span() token.Span {
+	span := joinSpan(c.Token, c.Function, nil)
+	span.End = c.End
+	return span
+}
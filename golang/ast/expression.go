@@ -25,3 +25,8 @@ func (es *ExpressionStatement) statementNode() {}
 func (es *ExpressionStatement) TokenLiteral() string {
 	return es.Token.Literal
 }
+
+// Span implements Node.
+func (es *ExpressionStatement) Span() token.Span {
+	return joinSpan(es.Token, es.Expression, es.Expression)
+}
@@ -0,0 +1,30 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import "github.com/w40141/monkey-language/golang/token"
+
+var _ Expression = (*FloatLiteral)(nil)
+
+// FloatLiteral represents a floating point literal in the AST.
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+// expressionNode implements Expression.
+func (f *FloatLiteral) expressionNode() {}
+
+// String implements Node.
+func (f *FloatLiteral) String() string {
+	return f.Token.Literal
+}
+
+// TokenLiteral implements Node.
+func (f *FloatLiteral) TokenLiteral() string {
+	return f.Token.Literal
+}
+
+// Span implements Node.
+func (f *FloatLiteral) Span() token.Span {
+	return f.Token.Span()
+}
@@ -13,22 +13,23 @@ var _ Expression = (*FunctionLiteral)(nil)
 // FunctionLiteral represents a function literal in the AST.
 type FunctionLiteral struct {
 	Token      token.Token
-	Parameters []*Identifier
-	Body       *BlockStatement
+	Parameters []Pattern
+	// Defaults holds the default value of each parameter, or nil if it has none.
+	Defaults []Expression
+	// Rest is the ...rest parameter collecting the remaining arguments, if any.
+	Rest *Identifier
+	Body *BlockStatement
+	// Name is the name the function is bound to by a let statement, if any.
+	Name string
 }
 
 // String implements Expression.
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
-	}
-
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(ParameterList(fl.Parameters, fl.Defaults, fl.Rest))
 	out.WriteString(") ")
 	out.WriteString(fl.Body.String())
 
@@ -42,3 +43,24 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 
 // expressionNode implements Expression.
 func (f *FunctionLiteral) expressionNode() {}
+
+// Span implements Node.
+func (fl *FunctionLiteral) Span() token.Span {
+	return joinSpan(fl.Token, nil, fl.Body)
+}
+
+// ParameterList returns the parameters of a function separated by commas.
+func ParameterList(params []Pattern, defaults []Expression, rest *Identifier) string {
+	list := []string{}
+	for i, p := range params {
+		if i < len(defaults) && defaults[i] != nil {
+			list = append(list, p.String()+" = "+defaults[i].String())
+			continue
+		}
+		list = append(list, p.String())
+	}
+	if rest != nil {
+		list = append(list, "..."+rest.String())
+	}
+	return strings.Join(list, ", ")
+}
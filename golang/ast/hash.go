@@ -10,10 +10,19 @@ import (
 
 var _ Expression = (*HashLiteral)(nil)
 
+// HashPair represents a key-value pair of a hash literal.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
 // HashLiteral represents a hash literal in the AST.
+// Pairs are kept in source order.
 type HashLiteral struct {
 	Token token.Token
-	Pairs map[Expression]Expression
+	Pairs []HashPair
+	// End is the position just past the closing brace.
+	End token.Position
 }
 
 // String implements Expression.
@@ -21,8 +30,8 @@ func (h *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range h.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
 	}
 
 	out.WriteString("{")
@@ -40,3 +49,8 @@ func (h *HashLiteral) TokenLiteral() string {
 // expressionNode implements Expression.
 func (h *HashLiteral) expressionNode() {
 }
+
+// Span implements Node.
+func (h *HashLiteral) Span() token.Span {
+	return token.Span{Start: h.Token.Start, End: h.End}
+}
@@ -25,3 +25,8 @@ func (i *Identifier) expressionNode() {}
 func (i *Identifier) TokenLiteral() string {
 	return i.Token.Literal
 }
+
+// Span implements Node.
+func (i *Identifier) Span() token.Span {
+	return i.Token.Span()
+}
@@ -10,11 +10,14 @@ import (
 var _ Expression = (*IfExpression)(nil)
 
 // IfExpression represents an if expression in the AST.
+// At most one of ALternative and ElseIf is set: ALternative holds the block
+// of a final else, ElseIf the next if of an else if chain.
 type IfExpression struct {
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
 	ALternative *BlockStatement
+	ElseIf      *IfExpression
 }
 
 // String implements Expression.
@@ -30,6 +33,10 @@ func (ie *IfExpression) String() string {
 		out.WriteString("else ")
 		out.WriteString(ie.ALternative.String())
 	}
+	if ie.ElseIf != nil {
+		out.WriteString("else ")
+		out.WriteString(ie.ElseIf.String())
+	}
 
 	return out.String()
 }
@@ -42,3 +49,13 @@ func (ie *IfExpression) TokenLiteral() string {
 // expressionNode implements Expression.
 func (ie *IfExpression) expressionNode() {}
 
+// Span implements Node.
+func (ie *IfExpression) Span() token.Span {
+	if ie.ALternative != nil {
+		return joinSpan(ie.Token, nil, ie.ALternative)
+	}
+	if ie.ElseIf != nil {
+		return joinSpan(ie.Token, nil, ie.ElseIf)
+	}
+	return joinSpan(ie.Token, nil, ie.Consequence)
+}
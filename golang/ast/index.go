@@ -14,6 +14,8 @@ type IndexExpression struct {
 	Token token.Token
 	Left  Expression
 	Index Expression
+	// End is the position just past the closing bracket.
+	End token.Position
 }
 
 // String implements Expression.
@@ -37,3 +39,10 @@ func (i *IndexExpression) TokenLiteral() string {
 // expressionNode implements Expression.
 func (i *IndexExpression) expressionNode() {
 }
+
+// Span implements Node.
+func (i *IndexExpression) Span() token.Span {
+	span := joinSpan(i.Token, i.Left, nil)
+	span.End = i.End
+	return span
+}
@@ -35,3 +35,8 @@ func (p *InfixExpression) TokenLiteral() string {
 
 // expressionNode implements Expression.
 func (p *InfixExpression) expressionNode() {}
+
+// Span implements Node.
+func (p *InfixExpression) Span() token.Span {
+	return joinSpan(p.Token, p.Left, p.Right)
+}
@@ -1,7 +1,11 @@
 // Package ast defines the abstract syntax tree for the Monkey programming language.
 package ast
 
-import "github.com/w40141/monkey-language/golang/token"
+import (
+	"math/big"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
 
 var _ Expression = (*IntegerLiteral)(nil)
 
@@ -9,6 +13,8 @@ var _ Expression = (*IntegerLiteral)(nil)
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
+	// Big holds the value instead of Value when it does not fit in int64.
+	Big *big.Int
 }
 
 // expressionNode implements Expression.
@@ -23,3 +29,8 @@ func (i *IntegerLiteral) String() string {
 func (i *IntegerLiteral) TokenLiteral() string {
 	return i.Token.Literal
 }
+
+// Span implements Node.
+func (i *IntegerLiteral) Span() token.Span {
+	return i.Token.Span()
+}
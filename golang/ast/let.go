@@ -9,12 +9,14 @@ import (
 
 var _ Statement = (*LetStatement)(nil)
 
-// LetStatement represents a let statement in the AST.
+// LetStatement represents a let or const statement in the AST.
 type LetStatement struct {
-	// Token is the token.LET token.
+	// Token is the token.LET or token.CONST token.
 	Token token.Token
 	Name  *Identifier
-	Value Expression
+	// Pattern is set instead of Name when the value is destructured.
+	Pattern Pattern
+	Value   Expression
 }
 
 // String implements Statement.
@@ -22,7 +24,11 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -39,3 +45,8 @@ func (ls *LetStatement) statementNode() {}
 func (ls *LetStatement) TokenLiteral() string {
 	return ls.Token.Literal
 }
+
+// Span implements Node.
+func (ls *LetStatement) Span() token.Span {
+	return joinSpan(ls.Token, nil, ls.Value)
+}
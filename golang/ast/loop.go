@@ -0,0 +1,127 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"bytes"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+var _ Expression = (*WhileExpression)(nil)
+
+// WhileExpression represents a while loop in the AST.
+type WhileExpression struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+// String implements Expression.
+func (we *WhileExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while")
+	out.WriteString(we.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(we.Body.String())
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (we *WhileExpression) TokenLiteral() string {
+	return we.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (we *WhileExpression) expressionNode() {}
+
+// Span implements Node.
+func (we *WhileExpression) Span() token.Span {
+	return joinSpan(we.Token, nil, we.Body)
+}
+
+var _ Expression = (*ForExpression)(nil)
+
+// ForExpression represents a for (x in iterable) loop in the AST.
+type ForExpression struct {
+	Token    token.Token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+// String implements Expression.
+func (fe *ForExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for(")
+	out.WriteString(fe.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(fe.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fe.Body.String())
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (fe *ForExpression) TokenLiteral() string {
+	return fe.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (fe *ForExpression) expressionNode() {}
+
+// Span implements Node.
+func (fe *ForExpression) Span() token.Span {
+	return joinSpan(fe.Token, nil, fe.Body)
+}
+
+var _ Statement = (*BreakStatement)(nil)
+
+// BreakStatement represents a break statement in the AST.
+type BreakStatement struct {
+	Token token.Token
+}
+
+// String implements Statement.
+func (bs *BreakStatement) String() string {
+	return bs.TokenLiteral() + ";"
+}
+
+func (bs *BreakStatement) statementNode() {}
+
+// TokenLiteral implements Statement.
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// Span implements Node.
+func (bs *BreakStatement) Span() token.Span {
+	return bs.Token.Span()
+}
+
+var _ Statement = (*ContinueStatement)(nil)
+
+// ContinueStatement represents a continue statement in the AST.
+type ContinueStatement struct {
+	Token token.Token
+}
+
+// String implements Statement.
+func (cs *ContinueStatement) String() string {
+	return cs.TokenLiteral() + ";"
+}
+
+func (cs *ContinueStatement) statementNode() {}
+
+// TokenLiteral implements Statement.
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+// Span implements Node.
+func (cs *ContinueStatement) Span() token.Span {
+	return cs.Token.Span()
+}
@@ -0,0 +1,74 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+var _ Expression = (*MatchExpression)(nil)
+
+// MatchArm is one pattern => body arm of a match expression. Guard, if set,
+// must be truthy for the arm to be taken.
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression
+	Body    Expression
+}
+
+// String returns the source form of the arm.
+func (ma MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" when ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+// MatchExpression represents a match expression in the AST.
+type MatchExpression struct {
+	Token   token.Token
+	Subject Expression
+	Arms    []MatchArm
+	// End is the position just past the closing brace.
+	End token.Position
+}
+
+// String implements Expression.
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match")
+	out.WriteString(me.Subject.String())
+	out.WriteString(" {")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (me *MatchExpression) expressionNode() {}
+
+// Span implements Node.
+func (me *MatchExpression) Span() token.Span {
+	return token.Span{Start: me.Token.Start, End: me.End}
+}
@@ -0,0 +1,169 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+// Pattern is the target of a binding in a let statement or a function
+// parameter: an *Identifier, an *ArrayPattern or a *HashPattern. The
+// patterns of a match arm may also be *LiteralPattern.
+type Pattern interface {
+	Expression
+	patternNode()
+}
+
+var (
+	_ Pattern = (*Identifier)(nil)
+	_ Pattern = (*ArrayPattern)(nil)
+	_ Pattern = (*HashPattern)(nil)
+	_ Pattern = (*LiteralPattern)(nil)
+)
+
+func (i *Identifier) patternNode() {}
+
+// PatternElement is a pattern with an optional default value, used when the
+// destructured value has no matching element or key.
+type PatternElement struct {
+	Target  Pattern
+	Default Expression
+}
+
+// String returns the source form of the element.
+func (pe PatternElement) String() string {
+	if pe.Default != nil {
+		return pe.Target.String() + " = " + pe.Default.String()
+	}
+	return pe.Target.String()
+}
+
+// ArrayPattern represents an array destructuring pattern such as
+// [a, b = 1, ...rest] in the AST.
+type ArrayPattern struct {
+	Token    token.Token
+	Elements []PatternElement
+	// Rest collects the remaining elements into an array, if set.
+	Rest *Identifier
+	// End is the position just past the closing bracket.
+	End token.Position
+}
+
+// String implements Expression.
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elems := []string{}
+	for _, el := range ap.Elements {
+		elems = append(elems, el.String())
+	}
+	if ap.Rest != nil {
+		elems = append(elems, "..."+ap.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elems, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+func (ap *ArrayPattern) expressionNode() {}
+
+func (ap *ArrayPattern) patternNode() {}
+
+// Span implements Node.
+func (ap *ArrayPattern) Span() token.Span {
+	return token.Span{Start: ap.Token.Start, End: ap.End}
+}
+
+// HashPatternEntry binds the value of Key to a pattern.
+type HashPatternEntry struct {
+	Key string
+	PatternElement
+}
+
+// String returns the source form of the entry. An entry binding a key to an
+// identifier of the same name is printed in its shorthand form.
+func (he HashPatternEntry) String() string {
+	if ident, ok := he.Target.(*Identifier); ok && ident.Value == he.Key {
+		return he.PatternElement.String()
+	}
+	return he.Key + ": " + he.PatternElement.String()
+}
+
+// HashPattern represents a hash destructuring pattern such as
+// {name, age: years, ...rest} in the AST.
+type HashPattern struct {
+	Token   token.Token
+	Entries []HashPatternEntry
+	// Rest collects the pairs with other keys into a hash, if set.
+	Rest *Identifier
+	// End is the position just past the closing brace.
+	End token.Position
+}
+
+// String implements Expression.
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	entries := []string{}
+	for _, entry := range hp.Entries {
+		entries = append(entries, entry.String())
+	}
+	if hp.Rest != nil {
+		entries = append(entries, "..."+hp.Rest.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(entries, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+
+func (hp *HashPattern) expressionNode() {}
+
+func (hp *HashPattern) patternNode() {}
+
+// Span implements Node.
+func (hp *HashPattern) Span() token.Span {
+	return token.Span{Start: hp.Token.Start, End: hp.End}
+}
+
+// LiteralPattern represents a match pattern that only matches values equal to
+// a literal such as 1, -2.5, "a" or true.
+type LiteralPattern struct {
+	Value Expression
+}
+
+// String implements Expression.
+func (lp *LiteralPattern) String() string {
+	return lp.Value.String()
+}
+
+// TokenLiteral implements Expression.
+func (lp *LiteralPattern) TokenLiteral() string {
+	return lp.Value.TokenLiteral()
+}
+
+func (lp *LiteralPattern) expressionNode() {}
+
+func (lp *LiteralPattern) patternNode() {}
+
+// Span implements Node.
+func (lp *LiteralPattern) Span() token.Span {
+	return lp.Value.Span()
+}
@@ -33,3 +33,8 @@ func (p *PrefixExpression) TokenLiteral() string {
 
 // expressionNode implements Expression.
 func (p *PrefixExpression) expressionNode() {}
+
+// Span implements Node.
+func (p *PrefixExpression) Span() token.Span {
+	return joinSpan(p.Token, nil, p.Right)
+}
@@ -1,13 +1,19 @@
 // Package ast defines the abstract syntax tree for the Monkey programming language.
 package ast
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
 
 var _ Node = (*Program)(nil)
 
 // Program represents a Monkey program.
 type Program struct {
 	Statements []Statement
+	// Comments holds the COMMENT tokens of the source in order of appearance.
+	Comments []token.Token
 }
 
 // String implements Node.
@@ -27,3 +33,13 @@ func (p *Program) TokenLiteral() string {
 	}
 	return ""
 }
+
+// Span returns the source range from the first to the last statement.
+func (p *Program) Span() token.Span {
+	if len(p.Statements) == 0 {
+		return token.Span{}
+	}
+	first := p.Statements[0].Span()
+	last := p.Statements[len(p.Statements)-1].Span()
+	return token.Span{Start: first.Start, End: last.End}
+}
@@ -36,3 +36,8 @@ func (rs *ReturnStatement) statementNode() {}
 func (rs *ReturnStatement) TokenLiteral() string {
 	return rs.Token.Literal
 }
+
+// Span implements Node.
+func (rs *ReturnStatement) Span() token.Span {
+	return joinSpan(rs.Token, nil, rs.ReturnValue)
+}
@@ -0,0 +1,56 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"bytes"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+var _ Expression = (*SliceExpression)(nil)
+
+// SliceExpression represents a slice expression such as a[1:3] in the AST.
+// Low and High are nil when omitted.
+type SliceExpression struct {
+	Token token.Token
+	Left  Expression
+	Low   Expression
+	High  Expression
+	// End is the position just past the closing bracket.
+	End token.Position
+}
+
+// String implements Expression.
+func (s *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Left.String())
+	out.WriteString("[")
+	if s.Low != nil {
+		out.WriteString(s.Low.String())
+	}
+	out.WriteString(":")
+	if s.High != nil {
+		out.WriteString(s.High.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (s *SliceExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (s *SliceExpression) expressionNode() {
+}
+
+// Span implements Node.
+func (s *SliceExpression) Span() token.Span {
+	span := joinSpan(s.Token, s.Left, nil)
+	span.End = s.End
+	return span
+}
@@ -0,0 +1,33 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+var _ Expression = (*SpreadExpression)(nil)
+
+// SpreadExpression represents ...value in an argument or element list. The
+// elements of the array value are inserted in its place.
+type SpreadExpression struct {
+	Token token.Token
+	Value Expression
+}
+
+// String implements Expression.
+func (s *SpreadExpression) String() string {
+	return "..." + s.Value.String()
+}
+
+// TokenLiteral implements Expression.
+func (s *SpreadExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (s *SpreadExpression) expressionNode() {}
+
+// Span implements Node.
+func (s *SpreadExpression) Span() token.Span {
+	return joinSpan(s.Token, nil, s.Value)
+}
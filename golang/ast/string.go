@@ -24,3 +24,8 @@ func (s *StringLiteral) TokenLiteral() string {
 // expressionNode implements Expression.
 func (s *StringLiteral) expressionNode() {
 }
+
+// Span implements Node.
+func (s *StringLiteral) Span() token.Span {
+	return s.Token.Span()
+}
@@ -0,0 +1,94 @@
+// Package ast defines the abstract syntax tree for the Monkey programming language.
+package ast
+
+import (
+	"bytes"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+var _ Statement = (*ThrowStatement)(nil)
+
+// ThrowStatement represents a throw statement in the AST.
+type ThrowStatement struct {
+	// Token is the token.THROW token.
+	Token token.Token
+	Value Expression
+}
+
+// String implements Statement.
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ts.TokenLiteral() + " ")
+	if ts.Value != nil {
+		out.WriteString(ts.Value.String())
+	}
+	out.WriteString(";")
+
+	return out.String()
+}
+
+func (ts *ThrowStatement) statementNode() {}
+
+// TokenLiteral returns the token literal of the throw statement.
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// Span implements Node.
+func (ts *ThrowStatement) Span() token.Span {
+	return joinSpan(ts.Token, nil, ts.Value)
+}
+
+var _ Expression = (*TryExpression)(nil)
+
+// TryExpression represents try { } catch (e) { } finally { } in the AST.
+// At least one of Catch and Finally is set; CatchParam is set with Catch.
+type TryExpression struct {
+	Token      token.Token
+	Block      *BlockStatement
+	CatchParam *Identifier
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+// String implements Expression.
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+	if te.Catch != nil {
+		out.WriteString("catch(")
+		out.WriteString(te.CatchParam.String())
+		out.WriteString(") ")
+		out.WriteString(te.Catch.String())
+	}
+	if te.Finally != nil {
+		out.WriteString("finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
+
+// TokenLiteral implements Expression.
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+
+// expressionNode implements Expression.
+func (te *TryExpression) expressionNode() {}
+
+// Span implements Node.
+func (te *TryExpression) Span() token.Span {
+	switch {
+	case te.Finally != nil:
+		return joinSpan(te.Token, nil, te.Finally)
+	case te.Catch != nil:
+		return joinSpan(te.Token, nil, te.Catch)
+	default:
+		return joinSpan(te.Token, nil, te.Block)
+	}
+}
@@ -0,0 +1,109 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// evalAssignExpression updates an existing binding or an element of an array
+// or hash and returns the assigned value. A compound operator such as +=
+// combines the current value with the new one first.
+func evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		return evalIdentifierAssignment(node, target, env)
+	case *ast.IndexExpression:
+		return evalIndexAssignment(node, target, env)
+	default:
+		return newError("cannot assign to %s", node.Target)
+	}
+}
+
+func evalIdentifierAssignment(node *ast.AssignExpression, target *ast.Identifier, env *object.Environment) object.Object {
+	current, ok := env.Get(target.Value)
+	if !ok {
+		return newError("identifier not found: %s", target.Value)
+	}
+	if env.IsConst(target.Value) {
+		return newError("cannot assign to constant %s", target.Value)
+	}
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+	val = combine(node.Operator, current, val, env)
+	if isError(val) {
+		return val
+	}
+	env.Assign(target.Value, val)
+	return val
+}
+
+func evalIndexAssignment(node *ast.AssignExpression, target *ast.IndexExpression, env *object.Environment) object.Object {
+	left := Eval(target.Left, env)
+	if isError(left) {
+		return left
+	}
+	index := Eval(target.Index, env)
+	if isError(index) {
+		return index
+	}
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		if left.Frozen {
+			return newError("cannot modify frozen %s", left.Type())
+		}
+		integer, ok := index.(*object.Integer)
+		if !ok {
+			return newError("index must be INTEGER, got %s", index.Type())
+		}
+		idx, ok := elementIndex(integer.Value, len(left.Elems))
+		if !ok {
+			return newError("index out of range: %d with length %d", integer.Value, len(left.Elems))
+		}
+		val = combine(node.Operator, left.Elems[idx], val, env)
+		if isError(val) {
+			return val
+		}
+		left.Elems[idx] = val
+		return val
+	case *object.Hash:
+		if left.Frozen {
+			return newError("cannot modify frozen %s", left.Type())
+		}
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+		if node.Operator != "=" {
+			pair, ok := left.Get(key)
+			if !ok {
+				return newError("key not found: %s", key.Inspect())
+			}
+			val = combine(node.Operator, pair.Value, val, env)
+			if isError(val) {
+				return val
+			}
+		}
+		left.Set(key, val)
+		return val
+	default:
+		return newError("index assignment not supported: %s", left.Type())
+	}
+}
+
+// combine applies the infix operator of a compound assignment operator to the
+// current and the new value. Plain = yields the new value.
+func combine(operator string, current, val object.Object, env *object.Environment) object.Object {
+	if operator == "=" {
+		return val
+	}
+	return evalInfixExpression(strings.TrimSuffix(operator, "="), current, val, env.Options())
+}
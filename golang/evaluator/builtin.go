@@ -1,39 +1,168 @@
 // Package evaluator contains the logic for evaluating the AST nodes.
 package evaluator
 
-import "github.com/w40141/monkey-language/golang/object"
+import (
+	"strings"
+	"unicode/utf8"
 
-var builtins = map[string]*object.Builtin{
-	"len": {
-		Fn: func(args ...object.Object) object.Object {
-			return lenBuiltin(args...)
-		},
-	},
-	"first": {
-		Fn: func(args ...object.Object) object.Object {
-			return firstBuiltin(args...)
-		},
-	},
-	"last": {
-		Fn: func(args ...object.Object) object.Object {
-			return lastBuiltin(args...)
-		},
-	},
-	"tail": {
-		Fn: func(args ...object.Object) object.Object {
-			return tailBuiltin(args...)
-		},
-	},
-	"push": {
-		Fn: func(args ...object.Object) object.Object {
-			return pushBuiltin(args...)
-		},
-	},
-	"puts": {
-		Fn: func(args ...object.Object) object.Object {
-			return putsBuiltin(args...)
-		},
-	},
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// builtins holds the builtin functions by name. It is filled in init because
+// some builtins call back into the evaluator, which looks names up here.
+var builtins map[string]*object.Builtin
+
+func init() {
+	builtins = map[string]*object.Builtin{
+		"len": {
+			Fn: func(args ...object.Object) object.Object {
+				return lenBuiltin(args...)
+			},
+		},
+		"first": {
+			Fn: func(args ...object.Object) object.Object {
+				return firstBuiltin(args...)
+			},
+		},
+		"last": {
+			Fn: func(args ...object.Object) object.Object {
+				return lastBuiltin(args...)
+			},
+		},
+		"tail": {
+			Fn: func(args ...object.Object) object.Object {
+				return tailBuiltin(args...)
+			},
+		},
+		"push": {
+			Fn: func(args ...object.Object) object.Object {
+				return pushBuiltin(args...)
+			},
+		},
+		"freeze": {
+			Fn: func(args ...object.Object) object.Object {
+				return freezeBuiltin(args...)
+			},
+		},
+		"puts": {
+			Fn: func(args ...object.Object) object.Object {
+				return putsBuiltin(args...)
+			},
+		},
+		"keys": {
+			Fn: func(args ...object.Object) object.Object {
+				return keysBuiltin(args...)
+			},
+		},
+		"values": {
+			Fn: func(args ...object.Object) object.Object {
+				return valuesBuiltin(args...)
+			},
+		},
+		"has": {
+			Fn: func(args ...object.Object) object.Object {
+				return hasBuiltin(args...)
+			},
+		},
+		"delete": {
+			Fn: func(args ...object.Object) object.Object {
+				return deleteBuiltin(args...)
+			},
+		},
+		"merge": {
+			Fn: func(args ...object.Object) object.Object {
+				return mergeBuiltin(args...)
+			},
+		},
+		"entries": {
+			Fn: func(args ...object.Object) object.Object {
+				return entriesBuiltin(args...)
+			},
+		},
+		"split": {
+			Fn: func(args ...object.Object) object.Object {
+				return splitBuiltin(args...)
+			},
+		},
+		"join": {
+			Fn: func(args ...object.Object) object.Object {
+				return joinBuiltin(args...)
+			},
+		},
+		"replace": {
+			Fn: func(args ...object.Object) object.Object {
+				return replaceBuiltin(args...)
+			},
+		},
+		"index_of": {
+			Fn: func(args ...object.Object) object.Object {
+				return indexOfBuiltin(args...)
+			},
+		},
+		"substring": {
+			Fn: func(args ...object.Object) object.Object {
+				return substringBuiltin(args...)
+			},
+		},
+		"map": {
+			Fn: func(args ...object.Object) object.Object {
+				return mapBuiltin(args...)
+			},
+		},
+		"filter": {
+			Fn: func(args ...object.Object) object.Object {
+				return filterBuiltin(args...)
+			},
+		},
+		"reduce": {
+			Fn: func(args ...object.Object) object.Object {
+				return reduceBuiltin(args...)
+			},
+		},
+		"any": {
+			Fn: func(args ...object.Object) object.Object {
+				return anyBuiltin(args...)
+			},
+		},
+		"all": {
+			Fn: func(args ...object.Object) object.Object {
+				return allBuiltin(args...)
+			},
+		},
+		"sort": {
+			Fn: func(args ...object.Object) object.Object {
+				return sortBuiltin(args...)
+			},
+		},
+		"zip": {
+			Fn: func(args ...object.Object) object.Object {
+				return zipBuiltin(args...)
+			},
+		},
+		"range": {
+			Fn: func(args ...object.Object) object.Object {
+				return rangeBuiltin(args...)
+			},
+		},
+		"flatten": {
+			Fn: func(args ...object.Object) object.Object {
+				return flattenBuiltin(args...)
+			},
+		},
+		"trim":        {Fn: stringFunction("trim", strings.TrimSpace)},
+		"upper":       {Fn: stringFunction("upper", strings.ToUpper)},
+		"lower":       {Fn: stringFunction("lower", strings.ToLower)},
+		"contains":    {Fn: stringPredicate("contains", strings.Contains)},
+		"starts_with": {Fn: stringPredicate("starts_with", strings.HasPrefix)},
+		"ends_with":   {Fn: stringPredicate("ends_with", strings.HasSuffix)},
+	}
+}
+
+func freezeBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+	return object.Freeze(args[0])
 }
 
 func putsBuiltin(args ...object.Object) object.Object {
@@ -108,6 +237,9 @@ func firstBuiltin(args ...object.Object) object.Object {
 	return nullObj
 }
 
+// lenBuiltin returns the number of elements of an array, pairs of a hash or
+// characters of a string. Strings are counted in characters, not bytes, like
+// indexing, slicing and the other string builtins.
 func lenBuiltin(args ...object.Object) object.Object {
 	if len(args) != 1 {
 		return newError("wrong number of arguments. got=%d, want=1", len(args))
@@ -117,7 +249,9 @@ func lenBuiltin(args ...object.Object) object.Object {
 	case *object.Array:
 		return &object.Integer{Value: int64(len(arg.Elems))}
 	case *object.String:
-		return &object.Integer{Value: int64(len(arg.Value))}
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *object.Hash:
+		return &object.Integer{Value: int64(arg.Len())}
 	default:
 		return newError("argument to `len` not supported, got %s", args[0].Type())
 	}
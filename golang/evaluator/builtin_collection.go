@@ -0,0 +1,277 @@
+package evaluator
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// arrayArgument checks that args holds want arguments and that the first one is an array.
+func arrayArgument(name string, want int, args []object.Object) (*object.Array, *object.Error) {
+	if len(args) != want {
+		return nil, newError("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, newError("argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+	return arr, nil
+}
+
+// callbackArgument checks that arg can be called.
+func callbackArgument(name string, arg object.Object) *object.Error {
+	switch arg.(type) {
+	case *object.Function, *object.Builtin:
+		return nil
+	default:
+		return newError("argument to `%s` must be FUNCTION, got %s", name, arg.Type())
+	}
+}
+
+// arrayAndCallback checks the usual (array, function) arguments.
+func arrayAndCallback(name string, args []object.Object) (*object.Array, object.Object, *object.Error) {
+	arr, err := arrayArgument(name, 2, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := callbackArgument(name, args[1]); err != nil {
+		return nil, nil, err
+	}
+	return arr, args[1], nil
+}
+
+func mapBuiltin(args ...object.Object) object.Object {
+	arr, fn, err := arrayAndCallback("map", args)
+	if err != nil {
+		return err
+	}
+	elems := make([]object.Object, len(arr.Elems))
+	for i, elem := range arr.Elems {
+		result := Apply(fn, elem)
+		if isError(result) {
+			return result
+		}
+		elems[i] = result
+	}
+	return &object.Array{Elems: elems}
+}
+
+func filterBuiltin(args ...object.Object) object.Object {
+	arr, fn, err := arrayAndCallback("filter", args)
+	if err != nil {
+		return err
+	}
+	elems := []object.Object{}
+	for _, elem := range arr.Elems {
+		result := Apply(fn, elem)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			elems = append(elems, elem)
+		}
+	}
+	return &object.Array{Elems: elems}
+}
+
+// reduceBuiltin folds the array from the left. Without an initial value the
+// first element is used, and an empty array is an error.
+func reduceBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+	}
+	arr, fn, err := arrayAndCallback("reduce", args[:2])
+	if err != nil {
+		return err
+	}
+	elems := arr.Elems
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elems) == 0 {
+			return newError("`reduce` of empty array with no initial value")
+		}
+		acc, elems = elems[0], elems[1:]
+	}
+	for _, elem := range elems {
+		acc = Apply(fn, acc, elem)
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+func anyBuiltin(args ...object.Object) object.Object {
+	arr, fn, err := arrayAndCallback("any", args)
+	if err != nil {
+		return err
+	}
+	for _, elem := range arr.Elems {
+		result := Apply(fn, elem)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) {
+			return trueObj
+		}
+	}
+	return falseObj
+}
+
+func allBuiltin(args ...object.Object) object.Object {
+	arr, fn, err := arrayAndCallback("all", args)
+	if err != nil {
+		return err
+	}
+	for _, elem := range arr.Elems {
+		result := Apply(fn, elem)
+		if isError(result) {
+			return result
+		}
+		if !isTruthy(result) {
+			return falseObj
+		}
+	}
+	return trueObj
+}
+
+// sortBuiltin returns a sorted copy of the array. Elements are compared with
+// < unless a comparator is given, which must return true when its first
+// argument goes before its second. The sort is stable.
+func sortBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+	arr, err := arrayArgument("sort", 1, args[:1])
+	if err != nil {
+		return err
+	}
+	less := func(a, b object.Object) object.Object {
+		return evalInfixExpression("<", a, b, object.Options{})
+	}
+	if len(args) == 2 {
+		if err := callbackArgument("sort", args[1]); err != nil {
+			return err
+		}
+		less = func(a, b object.Object) object.Object {
+			return Apply(args[1], a, b)
+		}
+	}
+
+	elems := make([]object.Object, len(arr.Elems))
+	copy(elems, arr.Elems)
+	var failure object.Object
+	sort.SliceStable(elems, func(i, j int) bool {
+		if failure != nil {
+			return false
+		}
+		result := less(elems[i], elems[j])
+		switch result := result.(type) {
+		case *object.Boolean:
+			return result.Value
+		case *object.Error:
+			failure = result
+		default:
+			failure = newError("comparator of `sort` must return BOOLEAN, got %s", result.Type())
+		}
+		return false
+	})
+	if failure != nil {
+		return failure
+	}
+	return &object.Array{Elems: elems}
+}
+
+// zipBuiltin pairs up the elements of two arrays. The result is as long as
+// the shorter array.
+func zipBuiltin(args ...object.Object) object.Object {
+	left, err := arrayArgument("zip", 2, args)
+	if err != nil {
+		return err
+	}
+	right, ok := args[1].(*object.Array)
+	if !ok {
+		return newError("argument to `zip` must be ARRAY, got %s", args[1].Type())
+	}
+	length := min(len(left.Elems), len(right.Elems))
+	elems := make([]object.Object, length)
+	for i := range length {
+		elems[i] = &object.Array{Elems: []object.Object{left.Elems[i], right.Elems[i]}}
+	}
+	return &object.Array{Elems: elems}
+}
+
+// maxRangeLength bounds the length of the array built by range, so that a
+// script cannot exhaust the memory of the host process.
+const maxRangeLength = 1 << 24
+
+// rangeBuiltin returns the integers from start up to, but not including, end,
+// counting by step. It accepts (end), (start, end) or (start, end, step).
+func rangeBuiltin(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newError("wrong number of arguments. got=%d, want=1, 2 or 3", len(args))
+	}
+	values := make([]int64, len(args))
+	for i, arg := range args {
+		integer, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("argument to `range` must be INTEGER, got %s", arg.Type())
+		}
+		values[i] = integer.Value
+	}
+	start, end, step := int64(0), values[0], int64(1)
+	if len(values) > 1 {
+		start, end = values[0], values[1]
+	}
+	if len(values) > 2 {
+		step = values[2]
+	}
+	if step == 0 {
+		return newError("step of `range` must not be zero")
+	}
+
+	count := rangeLength(start, end, step)
+	if !count.IsInt64() || count.Int64() > maxRangeLength {
+		return newError("range too large: %s elements", count)
+	}
+	elems := make([]object.Object, 0, count.Int64())
+	for i, n := start, count.Int64(); n > 0; i, n = i+step, n-1 {
+		elems = append(elems, &object.Integer{Value: i})
+	}
+	return &object.Array{Elems: elems}
+}
+
+// rangeLength returns the number of elements of range(start, end, step). It
+// is computed with big.Int because end - start may overflow int64.
+func rangeLength(start, end, step int64) *big.Int {
+	span := new(big.Int).Sub(big.NewInt(end), big.NewInt(start))
+	stride := big.NewInt(step)
+	if span.Sign() != stride.Sign() {
+		return new(big.Int)
+	}
+	span.Abs(span)
+	stride.Abs(stride)
+	span.Add(span, stride).Sub(span, big.NewInt(1))
+	return span.Quo(span, stride)
+}
+
+// flattenBuiltin removes one level of nesting: array elements are spliced in,
+// other elements are kept as they are.
+func flattenBuiltin(args ...object.Object) object.Object {
+	arr, err := arrayArgument("flatten", 1, args)
+	if err != nil {
+		return err
+	}
+	elems := []object.Object{}
+	for _, elem := range arr.Elems {
+		if inner, ok := elem.(*object.Array); ok {
+			elems = append(elems, inner.Elems...)
+		} else {
+			elems = append(elems, elem)
+		}
+	}
+	return &object.Array{Elems: elems}
+}
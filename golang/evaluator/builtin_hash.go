@@ -0,0 +1,110 @@
+package evaluator
+
+import "github.com/w40141/monkey-language/golang/object"
+
+// hashArgument checks that args holds want arguments and that the first one is a hash.
+func hashArgument(name string, want int, args []object.Object) (*object.Hash, *object.Error) {
+	if len(args) != want {
+		return nil, newError("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return nil, newError("argument to `%s` must be HASH, got %s", name, args[0].Type())
+	}
+	return hash, nil
+}
+
+// hashKeyArgument checks that arg can be used as a hash key.
+func hashKeyArgument(arg object.Object) (object.Hashable, *object.Error) {
+	key, ok := arg.(object.Hashable)
+	if !ok {
+		return nil, newError("unusable as hash key: %s", arg.Type())
+	}
+	return key, nil
+}
+
+func keysBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("keys", 1, args)
+	if err != nil {
+		return err
+	}
+	elems := make([]object.Object, 0, hash.Len())
+	for _, pair := range hash.Pairs() {
+		elems = append(elems, pair.Key)
+	}
+	return &object.Array{Elems: elems}
+}
+
+func valuesBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("values", 1, args)
+	if err != nil {
+		return err
+	}
+	elems := make([]object.Object, 0, hash.Len())
+	for _, pair := range hash.Pairs() {
+		elems = append(elems, pair.Value)
+	}
+	return &object.Array{Elems: elems}
+}
+
+func entriesBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("entries", 1, args)
+	if err != nil {
+		return err
+	}
+	elems := make([]object.Object, 0, hash.Len())
+	for _, pair := range hash.Pairs() {
+		elems = append(elems, &object.Array{Elems: []object.Object{pair.Key, pair.Value}})
+	}
+	return &object.Array{Elems: elems}
+}
+
+func hasBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("has", 2, args)
+	if err != nil {
+		return err
+	}
+	key, err := hashKeyArgument(args[1])
+	if err != nil {
+		return err
+	}
+	_, ok := hash.Get(key)
+	return nativeBoolToBooleanObject(ok)
+}
+
+// deleteBuiltin returns a copy of the hash without the given key.
+func deleteBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("delete", 2, args)
+	if err != nil {
+		return err
+	}
+	key, err := hashKeyArgument(args[1])
+	if err != nil {
+		return err
+	}
+	result := &object.Hash{}
+	for _, pair := range hash.Pairs() {
+		if !object.Equal(pair.Key, key) {
+			result.Set(pair.Key.(object.Hashable), pair.Value)
+		}
+	}
+	return result
+}
+
+// mergeBuiltin returns a new hash holding the pairs of both hashes. Values of
+// the second hash win for keys present in both.
+func mergeBuiltin(args ...object.Object) object.Object {
+	hash, err := hashArgument("merge", 2, args)
+	if err != nil {
+		return err
+	}
+	other, ok := args[1].(*object.Hash)
+	if !ok {
+		return newError("argument to `merge` must be HASH, got %s", args[1].Type())
+	}
+	result := &object.Hash{}
+	for _, pair := range append(hash.Pairs(), other.Pairs()...) {
+		result.Set(pair.Key.(object.Hashable), pair.Value)
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// stringArguments checks that args holds want strings and returns their values.
+func stringArguments(name string, want int, args []object.Object) ([]string, *object.Error) {
+	if len(args) != want {
+		return nil, newError("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	values := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, newError("argument to `%s` must be STRING, got %s", name, arg.Type())
+		}
+		values[i] = str.Value
+	}
+	return values, nil
+}
+
+// stringFunction wraps a string to string function as a builtin.
+func stringFunction(name string, fn func(string) string) func(args ...object.Object) object.Object {
+	return func(args ...object.Object) object.Object {
+		values, err := stringArguments(name, 1, args)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: fn(values[0])}
+	}
+}
+
+// stringPredicate wraps a predicate over two strings as a builtin.
+func stringPredicate(name string, fn func(string, string) bool) func(args ...object.Object) object.Object {
+	return func(args ...object.Object) object.Object {
+		values, err := stringArguments(name, 2, args)
+		if err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(fn(values[0], values[1]))
+	}
+}
+
+// splitBuiltin splits a string around a separator. An empty separator splits
+// the string into its characters.
+func splitBuiltin(args ...object.Object) object.Object {
+	values, err := stringArguments("split", 2, args)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(values[0], values[1])
+	elems := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elems[i] = &object.String{Value: part}
+	}
+	return &object.Array{Elems: elems}
+}
+
+func joinBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `join` must be ARRAY, got %s", args[0].Type())
+	}
+	sep, ok := args[1].(*object.String)
+	if !ok {
+		return newError("argument to `join` must be STRING, got %s", args[1].Type())
+	}
+	parts := make([]string, len(arr.Elems))
+	for i, elem := range arr.Elems {
+		str, ok := elem.(*object.String)
+		if !ok {
+			return newError("element of `join` must be STRING, got %s", elem.Type())
+		}
+		parts[i] = str.Value
+	}
+	return &object.String{Value: strings.Join(parts, sep.Value)}
+}
+
+func replaceBuiltin(args ...object.Object) object.Object {
+	values, err := stringArguments("replace", 3, args)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ReplaceAll(values[0], values[1], values[2])}
+}
+
+// indexOfBuiltin returns the character index of the first occurrence of a
+// substring, or -1.
+func indexOfBuiltin(args ...object.Object) object.Object {
+	values, err := stringArguments("index_of", 2, args)
+	if err != nil {
+		return err
+	}
+	i := strings.Index(values[0], values[1])
+	if i < 0 {
+		return &object.Integer{Value: -1}
+	}
+	return &object.Integer{Value: int64(utf8.RuneCountInString(values[0][:i]))}
+}
+
+// substringBuiltin returns the characters of a string from start up to, but
+// not including, end. end defaults to the length of the string.
+func substringBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3", len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `substring` must be STRING, got %s", args[0].Type())
+	}
+	runes := []rune(str.Value)
+	bounds := []int64{0, int64(len(runes))}
+	for i, arg := range args[1:] {
+		index, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("argument to `substring` must be INTEGER, got %s", arg.Type())
+		}
+		bounds[i] = index.Value
+	}
+	start, end := bounds[0], bounds[1]
+	if start < 0 || end < start || end > int64(len(runes)) {
+		return newError("substring out of range: [%d:%d] with length %d", start, end, len(runes))
+	}
+	return &object.String{Value: string(runes[start:end])}
+}
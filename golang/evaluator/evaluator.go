@@ -3,15 +3,21 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"unicode/utf8"
 
 	"github.com/w40141/monkey-language/golang/ast"
 	"github.com/w40141/monkey-language/golang/object"
+	"github.com/w40141/monkey-language/golang/token"
 )
 
 var (
-	nullObj  = &object.Null{}
-	trueObj  = &object.Boolean{Value: true}
-	falseObj = &object.Boolean{Value: false}
+	nullObj     = &object.Null{}
+	breakObj    = &object.Break{}
+	continueObj = &object.Continue{}
+	trueObj     = &object.Boolean{Value: true}
+	falseObj    = &object.Boolean{Value: false}
 )
 
 // Eval evaluates the AST node and returns an object.Object.
@@ -22,7 +28,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.IntegerLiteral:
+		if node.Big != nil {
+			return &object.BigInt{Value: node.Big}
+		}
 		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 	case *ast.PrefixExpression:
@@ -30,8 +41,11 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(r) {
 			return r
 		}
-		return evalPrefixExpression(node.Operator, r)
+		return locate(evalPrefixExpression(node.Operator, r, env.Options()), node.Token.Start)
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
 		l := Eval(node.Left, env)
 		if isError(l) {
 			return l
@@ -40,11 +54,25 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(r) {
 			return r
 		}
-		return evalInfixExpression(node.Operator, l, r)
+		return locate(evalInfixExpression(node.Operator, l, r, env.Options()), node.Token.Start)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.WhileExpression:
+		return evalWhileExpression(node, env)
+	case *ast.ForExpression:
+		return evalForExpression(node, env)
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+	case *ast.MatchExpression:
+		return locate(evalMatchExpression(node, env), node.Token.Start)
+	case *ast.BreakStatement:
+		return breakObj
+	case *ast.ContinueStatement:
+		return continueObj
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -52,17 +80,18 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return &object.Return{Value: val}
 	case *ast.LetStatement:
-		val := Eval(node.Value, env)
-		if isError(val) {
-			return val
-		}
-		return env.Set(node.Name.Value, val)
+		return evalLetStatement(node, env)
 	case *ast.Identifier:
-		return evalIdentifier(node, env)
+		return locate(evalIdentifier(node, env), node.Token.Start)
 	case *ast.FunctionLiteral:
-		params := node.Parameters
-		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{
+			Parameters: node.Parameters,
+			Defaults:   node.Defaults,
+			Rest:       node.Rest,
+			Env:        env,
+			Body:       node.Body,
+			Name:       node.Name,
+		}
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
 		if isError(function) {
@@ -72,7 +101,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		result := locate(applyFunction(function, args), node.Token.Start)
+		return addCallFrame(result, node, function)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 	case *ast.ArrayLiteral:
@@ -90,15 +120,25 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(index) {
 			return index
 		}
-		return evalIndexExpression(left, index)
+		return locate(evalIndexExpression(left, index), node.Token.Start)
+	case *ast.AssignExpression:
+		return locate(evalAssignExpression(node, env), node.Token.Start)
+	case *ast.SliceExpression:
+		return locate(evalSliceExpression(node, env), node.Token.Start)
 	case *ast.HashLiteral:
-		return evalHashLiteral(node, env)
+		return locate(evalHashLiteral(node, env), node.Token.Start)
 	}
 	return nil
 }
 
-func evalProgram(program *ast.Program, env *object.Environment) object.Object {
-	var result object.Object
+func evalProgram(program *ast.Program, env *object.Environment) (result object.Object) {
+	// A bug in the evaluator must not take down an embedding process.
+	defer func() {
+		if r := recover(); r != nil {
+			result = newError("internal error: %v", r)
+		}
+	}()
+
 	for _, stmt := range program.Statements {
 		result = Eval(stmt, env)
 		switch result := result.(type) {
@@ -117,7 +157,8 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 		result = Eval(stmt, env)
 		if result != nil {
 			rt := result.Type()
-			if rt == object.ReturnObj || rt == object.ErrorObj {
+			if rt == object.ReturnObj || rt == object.ErrorObj ||
+				rt == object.BreakObj || rt == object.ContinueObj {
 				return result
 			}
 		}
@@ -132,12 +173,14 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	return falseObj
 }
 
-func evalPrefixExpression(operator string, right object.Object) object.Object {
+func evalPrefixExpression(operator string, right object.Object, options object.Options) object.Object {
 	switch operator {
 	case "!":
 		return evalBangOperatorExpression(right)
 	case "-":
-		return evalMinusPrefixOperatorExpression(right)
+		return evalMinusPrefixOperatorExpression(right, options)
+	case "~":
+		return evalBitNotPrefixOperatorExpression(right)
 	default:
 		return newError("unknown operator: %s %s", operator, right.Type())
 	}
@@ -156,22 +199,48 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 	}
 }
 
-func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.IntegerObj {
+func evalMinusPrefixOperatorExpression(right object.Object, options object.Options) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.Value == math.MinInt64 {
+			if options.CheckedArithmetic {
+				return newError("integer overflow: -(%d)", right.Value)
+			}
+			return &object.BigInt{Value: new(big.Int).Neg(big.NewInt(right.Value))}
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Neg(right.Value))
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
 }
 
-func evalInfixExpression(operator string, left, right object.Object) object.Object {
+func evalBitNotPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^right.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Not(right.Value))
+	default:
+		return newError("unknown operator: ~%s", right.Type())
+	}
+}
+
+func evalInfixExpression(operator string, left, right object.Object, options object.Options) object.Object {
 	switch {
 	case left.Type() == object.IntegerObj && right.Type() == object.IntegerObj:
-		return evalIntegerInfixExpression(operator, left, right)
+		return evalIntegerInfixExpression(operator, left, right, options)
+	case isInteger(left) && isInteger(right):
+		return evalBigIntInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(object.Equal(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
 	case left.Type() == object.StringObj && right.Type() == object.StringObj:
 		return evalStringInfixExpression(operator, left, right)
 	case left.Type() != right.Type():
@@ -181,23 +250,60 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 	}
 }
 
-func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
+func evalIntegerInfixExpression(operator string, left, right object.Object, options object.Options) object.Object {
 	leftValue := left.(*object.Integer).Value
 	rightValue := right.(*object.Integer).Value
 
 	switch operator {
 	case "+":
-		return &object.Integer{Value: leftValue + rightValue}
+		value, ok := addInt64(leftValue, rightValue)
+		return integerResult(value, ok, "+", left, right, options)
 	case "-":
-		return &object.Integer{Value: leftValue - rightValue}
+		value, ok := subInt64(leftValue, rightValue)
+		return integerResult(value, ok, "-", left, right, options)
 	case "*":
-		return &object.Integer{Value: leftValue * rightValue}
+		value, ok := mulInt64(leftValue, rightValue)
+		return integerResult(value, ok, "*", left, right, options)
 	case "/":
-		return &object.Integer{Value: leftValue / rightValue}
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
+		ok := leftValue != math.MinInt64 || rightValue != -1
+		return integerResult(leftValue/rightValue, ok, "/", left, right, options)
+	case "%":
+		if rightValue == 0 {
+			return newError("modulo by zero")
+		}
+		return &object.Integer{Value: leftValue % rightValue}
+	case "**":
+		if rightValue < 0 {
+			return &object.Float{Value: math.Pow(float64(leftValue), float64(rightValue))}
+		}
+		value, ok := powInt64(leftValue, rightValue)
+		return integerResult(value, ok, "**", left, right, options)
+	case "&":
+		return &object.Integer{Value: leftValue & rightValue}
+	case "|":
+		return &object.Integer{Value: leftValue | rightValue}
+	case "^":
+		return &object.Integer{Value: leftValue ^ rightValue}
+	case "<<", ">>":
+		if rightValue < 0 {
+			return newError("negative shift count: %d", rightValue)
+		}
+		if operator == "<<" {
+			value, ok := shlInt64(leftValue, rightValue)
+			return integerResult(value, ok, "<<", left, right, options)
+		}
+		return &object.Integer{Value: leftValue >> rightValue}
 	case "<":
 		return nativeBoolToBooleanObject(leftValue < rightValue)
 	case ">":
 		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
 	case "==":
 		return nativeBoolToBooleanObject(leftValue == rightValue)
 	case "!=":
@@ -208,6 +314,94 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	}
 }
 
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftValue + rightValue}
+	case "-":
+		return &object.Float{Value: leftValue - rightValue}
+	case "*":
+		return &object.Float{Value: leftValue * rightValue}
+	case "/":
+		return &object.Float{Value: leftValue / rightValue}
+	case "%":
+		return &object.Float{Value: math.Mod(leftValue, rightValue)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftValue, rightValue)}
+	case "<", ">", "<=", ">=", "==", "!=":
+		return evalNumberComparison(operator, left, right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalNumberComparison compares numbers of any kind exactly, the same way
+// object.Equal does. Every comparison with NaN is false, except !=.
+func evalNumberComparison(operator string, left, right object.Object) object.Object {
+	c, ok := object.CompareNumbers(left, right)
+	if !ok {
+		return nativeBoolToBooleanObject(operator == "!=")
+	}
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(c < 0)
+	case ">":
+		return nativeBoolToBooleanObject(c > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(c <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(c >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(c == 0)
+	default:
+		return nativeBoolToBooleanObject(c != 0)
+	}
+}
+
+// isNumber reports whether obj is an integer or a float.
+func isNumber(obj object.Object) bool {
+	return isInteger(obj) || obj.Type() == object.FloatObj
+}
+
+// toFloat converts a numeric object to float64.
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInt:
+		f, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return f
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
+}
+
+// evalLogicalExpression evaluates && and ||. The right operand is only
+// evaluated when the left one does not decide the result.
+func evalLogicalExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+	if node.Operator == "&&" && !isTruthy(left) {
+		return falseObj
+	}
+	if node.Operator == "||" && isTruthy(left) {
+		return trueObj
+	}
+
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
+	}
+	return nativeBoolToBooleanObject(isTruthy(right))
+}
+
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
 	condition := Eval(ie.Condition, env)
 	if isError(condition) {
@@ -215,6 +409,8 @@ func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Obje
 	}
 	if isTruthy(condition) {
 		return Eval(ie.Consequence, env)
+	} else if ie.ElseIf != nil {
+		return Eval(ie.ElseIf, env)
 	} else if ie.ALternative != nil {
 		return Eval(ie.ALternative, env)
 	}
@@ -238,6 +434,15 @@ func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
 
+// locate attaches pos to obj if it is an error without a location yet.
+// The innermost failing expression therefore determines the reported position.
+func locate(obj object.Object, pos token.Position) object.Object {
+	if err, ok := obj.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = pos
+	}
+	return obj
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ErrorObj
@@ -259,6 +464,19 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	var result []object.Object
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			evaluated := Eval(spread.Value, env)
+			if isError(evaluated) {
+				return []object.Object{evaluated}
+			}
+			arr, ok := evaluated.(*object.Array)
+			if !ok {
+				err := newError("spread argument must be ARRAY, got %s", evaluated.Type())
+				return []object.Object{locate(err, spread.Token.Start)}
+			}
+			result = append(result, arr.Elems...)
+			continue
+		}
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -268,10 +486,20 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
+// Apply calls a Monkey function or builtin from Go with the given arguments
+// and returns its result. Failures, including a wrong number of arguments,
+// are reported as *object.Error.
+func Apply(fn object.Object, args ...object.Object) object.Object {
+	return applyFunction(fn, args)
+}
+
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch f := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(f, args)
+		extendedEnv, err := extendFunctionEnv(f, args)
+		if err != nil {
+			return err
+		}
 		evaluated := Eval(f.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
@@ -281,12 +509,64 @@ func applyFunction(fn object.Object, args []object.Object) object.Object {
 	}
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+// extendFunctionEnv binds the arguments of a call to the parameters of fn.
+// Missing arguments take their default value, evaluated in the new
+// environment so that it can refer to earlier parameters, and the rest
+// parameter collects the extra arguments into an array.
+func extendFunctionEnv(fn *object.Function, args []object.Object) (*object.Environment, object.Object) {
+	if err := checkArity(fn, len(args)); err != nil {
+		return nil, err
+	}
+
 	env := object.NewEnclosedEnvironment(fn.Env)
-	for id, param := range fn.Parameters {
-		env.Set(param.Value, args[id])
+	for i, param := range fn.Parameters {
+		var val object.Object
+		if i < len(args) {
+			val = args[i]
+		} else {
+			val = Eval(fn.Defaults[i], env)
+			if isError(val) {
+				return nil, val
+			}
+		}
+		if err := bindPattern(param, val, env); err != nil {
+			return nil, err
+		}
+	}
+	if fn.Rest != nil {
+		rest := []object.Object{}
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+		env.Set(fn.Rest.Value, &object.Array{Elems: rest})
+	}
+	return env, nil
+}
+
+// checkArity returns an error naming fn if it cannot be called with n arguments.
+func checkArity(fn *object.Function, n int) object.Object {
+	required := 0
+	for i := range fn.Parameters {
+		if i >= len(fn.Defaults) || fn.Defaults[i] == nil {
+			required = i + 1
+		}
+	}
+	if n >= required && (n <= len(fn.Parameters) || fn.Rest != nil) {
+		return nil
+	}
+
+	name := "anonymous function"
+	if fn.Name != "" {
+		name = "`" + fn.Name + "`"
+	}
+	want := fmt.Sprintf("want=%d", required)
+	switch {
+	case fn.Rest != nil:
+		want = fmt.Sprintf("want at least %d", required)
+	case required < len(fn.Parameters):
+		want = fmt.Sprintf("want=%d..%d", required, len(fn.Parameters))
 	}
-	return env
+	return newError("wrong number of arguments to %s. got=%d, %s", name, n, want)
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -297,18 +577,31 @@ func unwrapReturnValue(obj object.Object) object.Object {
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: STRING - STRING")
-	}
 	l := left.(*object.String).Value
 	r := right.(*object.String).Value
-	return &object.String{Value: l + r}
+
+	switch operator {
+	case "+":
+		return &object.String{Value: l + r}
+	case "<":
+		return nativeBoolToBooleanObject(l < r)
+	case ">":
+		return nativeBoolToBooleanObject(l > r)
+	case "<=":
+		return nativeBoolToBooleanObject(l <= r)
+	case ">=":
+		return nativeBoolToBooleanObject(l >= r)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ArrayObj && index.Type() == object.IntegerObj:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.StringObj && index.Type() == object.IntegerObj:
+		return evalStringIndexExpression(left, index)
 	case left.Type() == object.HashObj:
 		return evalHashIndexExpression(left, index)
 	default:
@@ -318,19 +611,98 @@ func evalIndexExpression(left, index object.Object) object.Object {
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arr := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	maxValue := int64(len(arr.Elems) - 1)
-	if idx < 0 || idx > maxValue {
+	idx, ok := elementIndex(index.(*object.Integer).Value, len(arr.Elems))
+	if !ok {
 		return nullObj
 	}
 	return arr.Elems[idx]
 }
 
+// evalStringIndexExpression returns the character at the index as a string.
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	idx, ok := elementIndex(index.(*object.Integer).Value, len(runes))
+	if !ok {
+		return nullObj
+	}
+	return &object.String{Value: string(runes[idx])}
+}
+
+// elementIndex resolves an index into a sequence of the given length. A
+// negative index counts from the end.
+func elementIndex(idx int64, length int) (int64, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
+	}
+	return idx, true
+}
+
+// evalSliceExpression evaluates a[low:high] on arrays and strings. Omitted
+// bounds default to the start and end, negative bounds count from the end,
+// and bounds outside the sequence are clamped to it.
+func evalSliceExpression(node *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elems)
+	case *object.String:
+		length = utf8.RuneCountInString(left.Value)
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+
+	low, err := sliceBound(node.Low, env, 0, length)
+	if err != nil {
+		return err
+	}
+	high, err := sliceBound(node.High, env, length, length)
+	if err != nil {
+		return err
+	}
+	high = max(high, low)
+
+	if arr, ok := left.(*object.Array); ok {
+		elems := make([]object.Object, high-low)
+		copy(elems, arr.Elems[low:high])
+		return &object.Array{Elems: elems}
+	}
+	runes := []rune(left.(*object.String).Value)
+	return &object.String{Value: string(runes[low:high])}
+}
+
+// sliceBound evaluates a slice bound and clamps it to [0, length].
+func sliceBound(node ast.Expression, env *object.Environment, def, length int) (int, object.Object) {
+	if node == nil {
+		return def, nil
+	}
+	obj := Eval(node, env)
+	if isError(obj) {
+		return 0, obj
+	}
+	bound, ok := obj.(*object.Integer)
+	if !ok {
+		return 0, locate(newError("slice index must be INTEGER, got %s", obj.Type()), node.Span().Start)
+	}
+	idx := bound.Value
+	if idx < 0 {
+		idx += int64(length)
+	}
+	return int(min(max(idx, 0), int64(length))), nil
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := &object.Hash{}
 
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
 		if isError(key) {
 			return key
 		}
@@ -339,13 +711,13 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 			return newError("unusable as hash key: %s", key.Type())
 		}
 
-		value := Eval(valueNode, env)
+		value := Eval(pair.Value, env)
 		if isError(value) {
 			return value
 		}
-		pairs[hashKey.HashKey()] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey, value)
 	}
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
 func evalHashIndexExpression(hash, index object.Object) object.Object {
@@ -354,7 +726,7 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 	if !ok {
 		return newError("unusable as hash key: %s", index.Type())
 	}
-	pair, ok := hashObj.Pairs[key.HashKey()]
+	pair, ok := hashObj.Get(key)
 	if !ok {
 		return nullObj
 	}
@@ -1,8 +1,10 @@
 package evaluator
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/w40141/monkey-language/golang/ast"
 	"github.com/w40141/monkey-language/golang/lexer"
 	"github.com/w40141/monkey-language/golang/object"
 	"github.com/w40141/monkey-language/golang/parser"
@@ -20,6 +22,8 @@ func TestHashIndexExpression(t *testing.T) {
 		{`{5: 5}[5]`, 5},
 		{`{true: 5}[true]`, 5},
 		{`{false: 5}[false]`, 5},
+		{`{"a": 1, "a": 2}["a"]`, 2},
+		{`{1: 1, true: 2}[1]`, 1},
 	}
 
 	for _, tt := range tests {
@@ -50,22 +54,22 @@ func TestHashLiterals(t *testing.T) {
 		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		(&object.Boolean{Value: true}).HashKey():   5,
-		(&object.Boolean{Value: false}).HashKey():  6,
+	expected := map[object.Hashable]int64{
+		&object.String{Value: "one"}:   1,
+		&object.String{Value: "two"}:   2,
+		&object.String{Value: "three"}: 3,
+		&object.Integer{Value: 4}:      4,
+		&object.Boolean{Value: true}:   5,
+		&object.Boolean{Value: false}:  6,
 	}
 
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	if result.Len() != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", result.Len())
 	}
 
 	t.Log(result)
 	for key, value := range expected {
-		pair, ok := result.Pairs[key]
+		pair, ok := result.Get(key)
 		if !ok {
 			t.Fatalf("no pair for given key in Pairs. key=%+v, value=%+v", key, value)
 		}
@@ -87,7 +91,9 @@ func TestArrayIndexExpressions(t *testing.T) {
 		{input: "let myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];", expected: 6},
 		{input: "let myArray = [1, 2, 3]; let i = myArray[0]; myArray[i]", expected: 2},
 		{input: "[1, 2, 3][3]", expected: nil},
-		{input: "[1, 2, 3][-1]", expected: nil},
+		{input: "[1, 2, 3][-1]", expected: 3},
+		{input: "[1, 2, 3][-3]", expected: 1},
+		{input: "[1, 2, 3][-4]", expected: nil},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +132,9 @@ func TestBuildinFunctions(t *testing.T) {
 		{input: `len("hello world")`, expected: 11},
 		{input: `len(1)`, expected: "argument to `len` not supported, got INTEGER"},
 		{input: `len("one", "two"),`, expected: "wrong number of arguments. got=2, want=1"},
+		{input: `len("日本語")`, expected: 3},
+		{input: `len({})`, expected: 0},
+		{input: `len({"a": 1, "b": 2})`, expected: 2},
 	}
 
 	for _, tt := range tests {
@@ -256,6 +265,7 @@ func TestLetStatements(t *testing.T) {
 		{"let a = 5 * 5; a;", 25},
 		{"let a = 5; let b = a; a", 5},
 		{"let a = 5; let b = a; let c = a + b; c", 10},
+		{"let 値 = 5; let größe = 値 * 2; größe", 10},
 	}
 
 	for _, tt := range tests {
@@ -281,6 +291,23 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"5 * 2 / 5", 2},
 		{"2 * (5 + 5)", 20},
 		{"4 * 5 / 2", 10},
+		{"7 / 2", 3},
+		{"17 % 5", 2},
+		{"-17 % 5", -2},
+		{"123 % 10 + 1", 4},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"5 ** 0", 1},
+		{"12 & 10", 8},
+		{"12 | 10", 14},
+		{"12 ^ 10", 6},
+		{"~5", -6},
+		{"1 << 10", 1024},
+		{"1024 >> 3", 128},
+		{"0xF0 | 0x0F", 255},
+		{"0b1010 & 0b0110", 2},
 	}
 
 	for _, tt := range tests {
@@ -289,6 +316,32 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.5", 3.5},
+		{"-2.5", -2.5},
+		{"1e3", 1000},
+		{"1.5 + 1.5", 3},
+		{"1 + 0.5", 1.5},
+		{"0.5 * 4", 2},
+		{"7 / 2.0", 3.5},
+		{"10 - 2.5 * 2", 5},
+		{"-(1.5 + 1)", -2.5},
+		{"7.5 % 2", 1.5},
+		{"2 ** 0.5 ** 2", 1.189207115002721},
+		{"2.0 ** 3", 8},
+		{"2 ** -1", 0.5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testFloatObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -313,6 +366,94 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"(1 < 2) == false", false},
 		{"(1 > 2) == true", false},
 		{"(1 > 2) == false", true},
+		{"1.5 < 2", true},
+		{"2 > 2.5", false},
+		{"1 == 1.0", true},
+		{"0.1 + 0.2 != 0.3", true},
+		{"1 <= 2", true},
+		{"2 <= 2", true},
+		{"3 <= 2", false},
+		{"1 >= 2", false},
+		{"2 >= 2", true},
+		{"2.5 >= 2", true},
+		{"1 <= 0.5", false},
+		{"true && true", true},
+		{"true && false", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"1 && 0", true},
+		{"false && foobar", false},
+		{"true || foobar", true},
+		{"let f = fn() { foobar }; false && f()", false},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"a" == "a"`, true},
+		{`"a" == "b"`, false},
+		{`"a" != "a"`, false},
+		{`let s = "ab"; s == "a" + "b"`, true},
+		{`[1] == [1]`, true},
+		{`[1, 2] == [1, 2, 3]`, false},
+		{`[1, [2, "x"]] == [1, [2, "x"]]`, true},
+		{`[1, [2, "x"]] == [1, [2, "y"]]`, false},
+		{`[1] == [1.0]`, true},
+		{`9007199254740993 == 9007199254740992.0`, false},
+		{`[9007199254740993] == [9007199254740992.0]`, false},
+		{`9007199254740993 != 9007199254740992.0`, true},
+		{`9007199254740993 > 9007199254740992.0`, true},
+		{`9007199254740992.0 < 9007199254740993`, true},
+		{`9007199254740992 == 9007199254740992.0`, true},
+		{`2 ** 70 == 1180591620717411303424.0`, true},
+		{`let nan = 0.0 / 0.0; nan == nan`, false},
+		{`let nan = 0.0 / 0.0; nan != nan`, true},
+		{`let nan = 0.0 / 0.0; nan < 1`, false},
+		{`[] == []`, true},
+		{`{"a": 1, "b": [2]} == {"b": [2], "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`{"a": {"b": [1]}} != {"a": {"b": [1]}}`, false},
+		{`[true, false] == [true, false]`, true},
+		{`"1" == 1`, false},
+		{`[1] == "[1]"`, false},
+		{`true == 1`, false},
+		{`let f = fn(x) { x }; f == f`, true},
+		{`fn(x) { x } == fn(x) { x }`, false},
+		{`len == len`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStringComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"abc" < "abd"`, true},
+		{`"ab" < "abc"`, true},
+		{`"B" < "a"`, true},
+		{`"b" > "a"`, true},
+		{`"a" <= "a"`, true},
+		{`"a" >= "b"`, false},
+		{`"" < "a"`, true},
+		{`"あ" > "z"`, true},
 	}
 
 	for _, tt := range tests {
@@ -352,6 +493,10 @@ func TestIfElseExpression(t *testing.T) {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
+		{"if (false) { 10 } else if (true) { 20 } else { 30 }", 20},
+		{"if (false) { 10 } else if (false) { 20 } else { 30 }", 30},
+		{"if (false) { 10 } else if (false) { 20 }", nil},
+		{"let x = 3; if (x == 1) { 10 } else if (x == 2) { 20 } else if (x == 3) { 30 } else { 40 }", 30},
 	}
 
 	for _, tt := range tests {
@@ -395,7 +540,22 @@ func TestErrorHanding(t *testing.T) {
 		{"5; true + false; 5", "unknown operator: BOOLEAN + BOOLEAN"},
 		{"if (10 > 1) { true + false; }", "unknown operator: BOOLEAN + BOOLEAN"},
 		{"foobar", "identifier not found: foobar"},
+		{"1.5 & 1", "unknown operator: FLOAT & INTEGER"},
+		{"~true", "unknown operator: ~BOOLEAN"},
+		{"1 << -1", "negative shift count: -1"},
+		{"1 / 0", "division by zero"},
+		{"2 ** 64 / 0", "division by zero"},
+		{"2 ** 64 % 0", "modulo by zero"},
+		{"2 ** 64 << -1", "negative shift count: -1"},
+		{"2 ** 99999999999", "integer too large: 2 ** 99999999999"},
+		{"(2 ** 64) << 99999999999", "integer too large: 18446744073709551616 << 99999999999"},
+		{"let zero = 0; 10 % zero", "modulo by zero"},
+		{"let f = fn(x) { 10 / x }; f(5) + f(0)", "division by zero"},
+		{"true && foobar", "identifier not found: foobar"},
+		{"false || foobar", "identifier not found: foobar"},
 		{`"foobar" - "sss"`, "unknown operator: STRING - STRING"},
+		{`"foobar" * "sss"`, "unknown operator: STRING * STRING"},
+		{`[1] < [2]`, "unknown operator: ARRAY < ARRAY"},
 		{`{"name": "Monkey"}[fn(x) { x }];`, "unusable as hash key: FUNCTION"},
 	}
 
@@ -412,6 +572,680 @@ func TestErrorHanding(t *testing.T) {
 	}
 }
 
+func TestIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		input     string
+		unchecked string
+		checked   string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808", "integer overflow: 9223372036854775807 + 1"},
+		{"-9223372036854775807 - 2", "-9223372036854775809", "integer overflow: -9223372036854775807 - 2"},
+		{"4611686018427387904 * 2", "9223372036854775808", "integer overflow: 4611686018427387904 * 2"},
+		{"2 ** 64", "18446744073709551616", "integer overflow: 2 ** 64"},
+		{"1 << 63", "9223372036854775808", "integer overflow: 1 << 63"},
+		{"1 << 64", "18446744073709551616", "integer overflow: 1 << 64"},
+		{"-3 << 62", "-13835058055282163712", "integer overflow: -3 << 62"},
+		{"let min = -9223372036854775807 - 1; min / -1", "9223372036854775808", "integer overflow: -9223372036854775808 / -1"},
+		{"let min = -9223372036854775807 - 1; -min", "9223372036854775808", "integer overflow: -(-9223372036854775808)"},
+	}
+
+	for _, tt := range tests {
+		testBigIntObject(t, testEval(tt.input), tt.unchecked)
+	}
+
+	checked := object.Options{CheckedArithmetic: true}
+	for _, tt := range tests {
+		env := object.NewEnvironmentWithOptions(checked)
+		evaluated := Eval(parser.New(lexer.New(tt.input)).ParseProgram(), env)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.checked {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.checked, errObj.Message)
+		}
+	}
+
+	testIntegerObject(t, testEval("9223372036854775806 + 1"), 9223372036854775807)
+	testIntegerObject(t, testEval("-3037000499 * 3037000499"), -9223372030926249001)
+	testIntegerObject(t, testEval("3 ** 39"), 4052555153018976267)
+	testIntegerObject(t, testEval("1 << 62"), 4611686018427387904)
+	testIntegerObject(t, testEval("-1 << 63"), -9223372036854775808)
+	testIntegerObject(t, testEval("0 << 100"), 0)
+
+	env := object.NewEnvironmentWithOptions(checked)
+	evaluated := Eval(parser.New(lexer.New("let f = fn(x) { x += 1 }; f(9223372036854775807)")).ParseProgram(), env)
+	if got := evaluated.Inspect(); got != "ERROR: 1:19: integer overflow: 9223372036854775807 + 1" {
+		t.Errorf("function body not evaluated in checked mode. got=%q", got)
+	}
+}
+
+func TestBigIntArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"99999999999999999999", "99999999999999999999"},
+		{"0xFFFFFFFFFFFFFFFFFF", "4722366482869645213695"},
+		{"99999999999999999999 + 1", "100000000000000000000"},
+		{"1 + 99999999999999999999", "100000000000000000000"},
+		{"99999999999999999999 - 99999999999999999998", 1},
+		{"9223372036854775807 + 1 - 1", 9223372036854775807},
+		{"(2 ** 64) / (2 ** 60)", 16},
+		{"(2 ** 64 + 5) % 2 ** 64", 5},
+		{"-(2 ** 64)", "-18446744073709551616"},
+		{"-(-(2 ** 63))", "9223372036854775808"},
+		{"~(2 ** 64)", "-18446744073709551617"},
+		{"(2 ** 64) >> 60", 16},
+		{"(2 ** 64) >> 100", 0},
+		{"(2 ** 64) << 1", "36893488147419103232"},
+		{"(2 ** 64) & 0xFF", 0},
+		{"(2 ** 64) | 1", "18446744073709551617"},
+		{"3 ** 50", "717897987691852588770249"},
+		{"(-2) ** 63", -9223372036854775808},
+		{"(-2) ** 65", "-36893488147419103232"},
+		{
+			"let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } }; fact(25)",
+			"15511210043330985984000000",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testBigIntObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestBigIntComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"2 ** 64 > 1", true},
+		{"1 < 2 ** 64", true},
+		{"2 ** 64 == 2 ** 64", true},
+		{"2 ** 64 != 2 ** 64 + 1", true},
+		{"2 ** 64 <= 2 ** 63", false},
+		{"-(2 ** 64) >= 0", false},
+		{"2 ** 64 > 1.5", true},
+		{"2 ** 64 == 18446744073709551616.0", true},
+		{"9223372036854775807 + 1 - 1 == 9223372036854775807", true},
+	}
+
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBigIntHashKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{`{2 ** 64: 1}[2 ** 64]`, 1},
+		{`{99999999999999999999: 2}[99999999999999999998 + 1]`, 2},
+		{`{5: 3}[2 ** 64 - 2 ** 64 + 5]`, 3},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys({"b": 1, "a": 2})`, `[b, a]`},
+		{`keys({})`, `[]`},
+		{`values({"b": 1, "a": 2})`, `[1, 2]`},
+		{`entries({"b": 1, 2: true})`, `[[b, 1], [2, true]]`},
+		{`has({"a": 1}, "a")`, `true`},
+		{`has({"a": 1}, "b")`, `false`},
+		{`has({1: 1}, 2 ** 64 - 2 ** 64 + 1)`, `true`},
+		{`delete({"a": 1, "b": 2, "c": 3}, "b")`, `{a: 1, c: 3}`},
+		{`delete({"a": 1}, "z")`, `{a: 1}`},
+		{`let h = {"a": 1}; delete(h, "a"); h`, `{a: 1}`},
+		{`merge({"a": 1, "b": 2}, {"b": 3, "c": 4})`, `{a: 1, b: 3, c: 4}`},
+		{`let h = {"a": 1}; merge(h, {"b": 2}); h`, `{a: 1}`},
+		{`keys([1])`, "ERROR: 1:5: argument to `keys` must be HASH, got ARRAY"},
+		{`values(1)`, "ERROR: 1:7: argument to `values` must be HASH, got INTEGER"},
+		{`entries()`, "ERROR: 1:8: wrong number of arguments. got=0, want=1"},
+		{`has({}, [])`, "ERROR: 1:4: unusable as hash key: ARRAY"},
+		{`delete({}, fn(x) { x })`, "ERROR: 1:7: unusable as hash key: FUNCTION"},
+		{`merge({}, "a")`, "ERROR: 1:6: argument to `merge` must be HASH, got STRING"},
+		{`merge({})`, "ERROR: 1:6: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a,b,,c", ",")`, `[a, b, , c]`},
+		{`split("日本語", "")`, `[日, 本, 語]`},
+		{`split("", ",")`, `[]`},
+		{`join(["a", "b", "c"], "-")`, `a-b-c`},
+		{`join([], "-")`, ``},
+		{`join(split("héllo wörld", " "), "_")`, `héllo_wörld`},
+		{`trim("\u{3000} hi\t\n")`, `hi`},
+		{`upper("héllo ÿ")`, `HÉLLO Ÿ`},
+		{`lower("ÀÉÎ")`, `àéî`},
+		{`contains("日本語", "本")`, `true`},
+		{`contains("abc", "d")`, `false`},
+		{`starts_with("日本語", "日本")`, `true`},
+		{`ends_with("日本語", "本")`, `false`},
+		{`index_of("日本語", "語")`, `2`},
+		{`index_of("abc", "z")`, `-1`},
+		{`replace("a-b-c", "-", "+")`, `a+b+c`},
+		{`substring("日本語テキスト", 1, 3)`, `本語`},
+		{`substring("日本語", 1)`, `本語`},
+		{`substring("abc", 3)`, ``},
+		{`substring("日本語", 0, len("日本語"))`, `日本語`},
+		{`let s = "héllo"; s[len(s) - 1]`, `o`},
+		{`substring("abc", 2, 1)`, "ERROR: 1:10: substring out of range: [2:1] with length 3"},
+		{`substring("日本語", 0, 4)`, "ERROR: 1:10: substring out of range: [0:4] with length 3"},
+		{`substring("abc", "1")`, "ERROR: 1:10: argument to `substring` must be INTEGER, got STRING"},
+		{`substring("abc")`, "ERROR: 1:10: wrong number of arguments. got=1, want=2 or 3"},
+		{`upper(1)`, "ERROR: 1:6: argument to `upper` must be STRING, got INTEGER"},
+		{`contains("a")`, "ERROR: 1:9: wrong number of arguments. got=1, want=2"},
+		{`join(["a", 1], "")`, "ERROR: 1:5: element of `join` must be STRING, got INTEGER"},
+		{`join("a", "")`, "ERROR: 1:5: argument to `join` must be ARRAY, got STRING"},
+		{`replace("a", "b")`, "ERROR: 1:8: wrong number of arguments. got=2, want=3"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`map([1, 2, 3], fn(x) { x * 2 })`, `[2, 4, 6]`},
+		{`map([], fn(x) { x })`, `[]`},
+		{`map(["a", "b"], upper)`, `[A, B]`},
+		{`len(map(range(100000), fn(x) { x + 1 }))`, `100000`},
+		{`filter([1, 2, 3, 4], fn(x) { x % 2 == 0 })`, `[2, 4]`},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc + x }, 10)`, `20`},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc * x })`, `24`},
+		{`reduce([], fn(acc, x) { acc + x }, 0)`, `0`},
+		{`any([1, 2, 3], fn(x) { x > 2 })`, `true`},
+		{`any([], fn(x) { true })`, `false`},
+		{`all([1, 2, 3], fn(x) { x > 0 })`, `true`},
+		{`all([1, 2, 3], fn(x) { x > 1 })`, `false`},
+		{`sort([3, 1.5, 2])`, `[1.5, 2, 3]`},
+		{`sort(["b", "c", "a"])`, `[a, b, c]`},
+		{`sort([3, 1, 2], fn(a, b) { a > b })`, `[3, 2, 1]`},
+		{`sort([[2, "a"], [1, "b"], [2, "c"]], fn(a, b) { a[0] < b[0] })`, `[[1, b], [2, a], [2, c]]`},
+		{`let a = [2, 1]; sort(a); a`, `[2, 1]`},
+		{`zip([1, 2, 3], ["a", "b"])`, `[[1, a], [2, b]]`},
+		{`range(4)`, `[0, 1, 2, 3]`},
+		{`range(2, 5)`, `[2, 3, 4]`},
+		{`range(5, 0, -2)`, `[5, 3, 1]`},
+		{`range(3, 1)`, `[]`},
+		{`range(9223372036854775806, 9223372036854775807, 5)`, `[9223372036854775806]`},
+		{`flatten([1, [2, 3], [[4]], []])`, `[1, 2, 3, [4]]`},
+		{`map([1], fn(x) { x + true })`, "ERROR: 1:20: type mismatch: INTEGER + BOOLEAN"},
+		{`map([1], fn(x, y) { x })`, "ERROR: 1:4: wrong number of arguments to anonymous function. got=1, want=2"},
+		{`map([1], 1)`, "ERROR: 1:4: argument to `map` must be FUNCTION, got INTEGER"},
+		{`filter(1, fn(x) { x })`, "ERROR: 1:7: argument to `filter` must be ARRAY, got INTEGER"},
+		{`reduce([], fn(acc, x) { acc })`, "ERROR: 1:7: `reduce` of empty array with no initial value"},
+		{`sort([1, "a"])`, "ERROR: 1:5: type mismatch: STRING < INTEGER"},
+		{`sort([1, 2], fn(a, b) { 1 })`, "ERROR: 1:5: comparator of `sort` must return BOOLEAN, got INTEGER"},
+		{`range(0, 1, 0)`, "ERROR: 1:6: step of `range` must not be zero"},
+		{`range(9223372036854775807)`, "ERROR: 1:6: range too large: 9223372036854775807 elements"},
+		{`range(-9223372036854775807 - 1, 9223372036854775807, 3)`, "ERROR: 1:6: range too large: 6148914691236517205 elements"},
+		{`range(0, 16777217)`, "ERROR: 1:6: range too large: 16777217 elements"},
+		{`len(range(-9223372036854775807 - 1, 9223372036854775807, 1 << 60))`, `16`},
+		{`range("a")`, "ERROR: 1:6: argument to `range` must be INTEGER, got STRING"},
+		{`zip([1])`, "ERROR: 1:4: wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	fn := testEval("fn(a, b) { a * 10 + b }")
+
+	testIntegerObject(t, Apply(fn, &object.Integer{Value: 4}, &object.Integer{Value: 2}), 42)
+	testIntegerObject(t, Apply(builtins["len"], &object.String{Value: "abc"}), 3)
+
+	result := Apply(fn, &object.Integer{Value: 4})
+	if result.Inspect() != "ERROR: wrong number of arguments to anonymous function. got=1, want=2" {
+		t.Errorf("wrong result. got=%q", result.Inspect())
+	}
+}
+
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`"abc"[1]`, "b"},
+		{`"日本語"[2]`, "語"},
+		{`"abc"[-1]`, "c"},
+		{`"abc"[3]`, nil},
+		{`""[0]`, nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := tt.expected.(string)
+		if !ok {
+			testNullObject(t, evaluated)
+			continue
+		}
+		result, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if result.Value != str {
+			t.Errorf("String has wrong value. expected=%q, got=%q", str, result.Value)
+		}
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[1, 2, 3, 4][1:3]`, `[2, 3]`},
+		{`[1, 2, 3, 4][:2]`, `[1, 2]`},
+		{`[1, 2, 3, 4][2:]`, `[3, 4]`},
+		{`[1, 2, 3, 4][:]`, `[1, 2, 3, 4]`},
+		{`[1, 2, 3, 4][-2:]`, `[3, 4]`},
+		{`[1, 2, 3, 4][:-1]`, `[1, 2, 3]`},
+		{`[1, 2, 3, 4][-10:10]`, `[1, 2, 3, 4]`},
+		{`[1, 2, 3, 4][3:1]`, `[]`},
+		{`let a = [1, 2, 3]; let i = 1; a[i:i + 1]`, `[2]`},
+		{`"hello"[1:3]`, `el`},
+		{`"日本語テキスト"[3:]`, `テキスト`},
+		{`"abc"[:-1]`, `ab`},
+		{`"abc"[5:]`, ``},
+		{`1[0:1]`, "ERROR: 1:2: slice operator not supported: INTEGER"},
+		{`[1, 2]["a":]`, "ERROR: 1:8: slice index must be INTEGER, got STRING"},
+		{`[1, 2][:x]`, "ERROR: 1:9: identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let x = 1; x = 2; x`, `2`},
+		{`let x = 1; x = x + 1`, `2`},
+		{`let x = 1; let y = 1; x = y = 5; x + y`, `10`},
+		{`let x = 10; x += 5; x -= 3; x *= 2; x /= 4; x`, `6`},
+		{`let x = 7; x %= 4; x **= 3; x`, `27`},
+		{`let x = 6; x &= 3; x |= 8; x ^= 1; x <<= 2; x >>= 1; x`, `22`},
+		{`let s = "a"; s += "b"; s`, `ab`},
+		{`let x = 1; let f = fn() { x = x + 1 }; f(); f(); x`, `3`},
+		{`let counter = fn() { let n = 0; fn() { n += 1 } }; let c = counter(); c(); c()`, `2`},
+		{`let x = 1; let f = fn() { let x = 5; x = 6 }; f(); x`, `1`},
+		{`let a = [1, 2, 3]; a[0] = 10; a[-1] += 5; a`, `[10, 2, 8]`},
+		{`let a = [1, 2]; let b = a; b[0] = 3; a`, `[3, 2]`},
+		{`let h = {"a": 1}; h["b"] = 2; h["a"] += 10; h`, `{a: 11, b: 2}`},
+		{`let h = {"a": [1]}; h["a"][0] = 2; h`, `{a: [2]}`},
+		{`let a = [1]; a[0] = a; a`, `[[...]]`},
+		{`let a = [1, 2]; a[1] = a; a[0] = a; a`, `[[...], [...]]`},
+		{`let h = {"a": 1}; h["self"] = h; h`, `{a: 1, self: {...}}`},
+		{`let a = [0]; let h = {"a": a}; a[0] = h; [h, a]`, `[{a: [{...}]}, [{a: [...]}]]`},
+		{`let x = [1]; [x, x]`, `[[1], [1]]`},
+		{`x = 1`, "ERROR: 1:3: identifier not found: x"},
+		{`x += 1`, "ERROR: 1:3: identifier not found: x"},
+		{`let x = 1; x += "a"`, "ERROR: 1:14: type mismatch: INTEGER + STRING"},
+		{`let a = [1]; a[1] = 2`, "ERROR: 1:19: index out of range: 1 with length 1"},
+		{`let a = [1]; a["x"] = 2`, "ERROR: 1:21: index must be INTEGER, got STRING"},
+		{`let h = {}; h["a"] += 1`, "ERROR: 1:20: key not found: a"},
+		{`let h = {}; h[[]] = 1`, "ERROR: 1:19: unusable as hash key: ARRAY"},
+		{`let s = "abc"; s[0] = "x"`, "ERROR: 1:21: index assignment not supported: STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestLoopExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let i = 0; let sum = 0; while (i < 5) { sum += i; i += 1 }; sum`, `10`},
+		{`while (false) { 1 }`, `null`},
+		{`let i = 0; while (true) { i += 1; if (i == 3) { break } }; i`, `3`},
+		{`let i = 0; let odd = 0; while (i < 6) { i += 1; if (i % 2 == 0) { continue; } odd += 1 }; odd`, `3`},
+		{`let sum = 0; for (x in [1, 2, 3]) { sum += x }; sum`, `6`},
+		{`let out = ""; for (k in {"b": 1, "a": 2}) { out += k }; out`, `ba`},
+		{`let out = []; for (c in "日本") { out = push(out, c) }; out`, `[日, 本]`},
+		{`let n = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { break } n += x }; n`, `3`},
+		{`let n = 0; for (x in [1, 2, 3, 4]) { if (x % 2 == 0) { continue } n += x }; n`, `4`},
+		{`let f = fn() { for (x in [1, 2, 3]) { if (x == 2) { return x * 10 } } 0 }; f()`, `20`},
+		{`let f = fn() { while (true) { return 7 } }; f()`, `7`},
+		{`let n = 0; for (x in [[1, 2], [3]]) { for (y in x) { if (y == 2) { break } n += y } }; n`, `4`},
+		{`let out = []; for (x in [1, 2]) { out = push(out, fn() { x }) }; out[0]() + out[1]()`, `3`},
+		{`let a = [1, 2]; let n = 0; for (x in a) { a[1] = 5; n += x }; n`, `6`},
+		{`let i = 0; while (i < 100000) { i += 1 }; i`, `100000`},
+		{`for (x in 1) { x }`, "ERROR: 1:11: not iterable: INTEGER"},
+		{`while (y) { 1 }`, "ERROR: 1:8: identifier not found: y"},
+		{`for (x in [1]) { x + true }`, "ERROR: 1:20: type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"z": 1, "a": 2, "m": 3}`, `{z: 1, a: 2, m: 3}`},
+		{`{3: "c", 1: "a", 2: "b"}`, `{3: c, 1: a, 2: b}`},
+		{`{"b": 1, "a": 2, "b": 3}`, `{b: 3, a: 2}`},
+		{`{"a": 1 / 0, 1 % 0: 2}`, "ERROR: 1:9: division by zero"},
+		{`{x: 1, "a": y}`, "ERROR: 1:2: identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output. expected=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestFunctionArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let f = fn(x, y = 10) { x + y }; f(1)`, `11`},
+		{`let f = fn(x, y = 10) { x + y }; f(1, 2)`, `3`},
+		{`let f = fn(x = 1, y = x * 2) { [x, y] }; f()`, `[1, 2]`},
+		{`let f = fn(x = 1, y = x * 2) { [x, y] }; f(5)`, `[5, 10]`},
+		{`let n = 0; let f = fn(x = n += 1) { x }; f(); f(); f(7); n`, `2`},
+		{`let f = fn(first, ...rest) { [first, rest] }; f(1, 2, 3)`, `[1, [2, 3]]`},
+		{`let f = fn(first, ...rest) { rest }; f(1)`, `[]`},
+		{`let f = fn(a = 0, ...r) { [a, r] }; f()`, `[0, []]`},
+		{`let add = fn(a, b, c) { a + b + c }; add(...[1, 2, 3])`, `6`},
+		{`let add = fn(a, b, c) { a + b + c }; add(1, ...[2], ...[3])`, `6`},
+		{`let f = fn(...xs) { len(xs) }; f(...[], ...[1, 2], 3)`, `3`},
+		{`[0, ...[1, 2], 3]`, `[0, 1, 2, 3]`},
+		{`push(...[[1], 2])`, `[1, 2]`},
+		{`let f = fn(x, y) { x }; f(1)`, "ERROR: 1:26: wrong number of arguments to `f`. got=1, want=2"},
+		{`let f = fn(x) { x }; f(1, 2)`, "ERROR: 1:23: wrong number of arguments to `f`. got=2, want=1"},
+		{`fn(x, y = 1) { x }()`, "ERROR: 1:19: wrong number of arguments to anonymous function. got=0, want=1..2"},
+		{`let f = fn(x, ...r) { x }; f()`, "ERROR: 1:29: wrong number of arguments to `f`. got=0, want at least 1"},
+		{`let f = fn(x = y) { x }; f()`, "ERROR: 1:16: identifier not found: y"},
+		{`let f = fn(x) { x }; f(...1)`, "ERROR: 1:24: spread argument must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [a, b] = [1, 2]; a + b`, `3`},
+		{`let [a, b] = [1]; b`, `null`},
+		{`let [a, b = 5] = [1]; b`, `5`},
+		{`let [a, b = 5] = [1, 2]; b`, `2`},
+		{`let [first, ...rest] = [1, 2, 3]; rest`, `[2, 3]`},
+		{`let [x, ...rest] = []; [x, rest]`, `[null, []]`},
+		{`let [a, [b, c]] = [1, [2, 3]]; a + b + c`, `6`},
+		{`let [a, b = a * 10] = [2]; b`, `20`},
+		{`let {name, age: years} = {"name": "ann", "age": 30}; [name, years]`, `[ann, 30]`},
+		{`let {x, y = 0} = {"x": 1}; [x, y]`, `[1, 0]`},
+		{`let {missing} = {}; missing`, `null`},
+		{`let {a, ...others} = {"a": 1, "b": 2, "c": 3}; others`, `{b: 2, c: 3}`},
+		{`let {"first name": first} = {"first name": "bo"}; first`, `bo`},
+		{`let {pos: {x, y}, tags: [tag]} = {"pos": {"x": 1, "y": 2}, "tags": ["t"]}; [x, y, tag]`, `[1, 2, t]`},
+		{`let pair = fn() { [1, 2] }; let [q, r] = pair(); q * 10 + r`, `12`},
+		{`let f = fn([a, b], {c}) { a + b + c }; f([1, 2], {"c": 3})`, `6`},
+		{`let f = fn({size = 1} = {}) { size }; [f(), f({}), f({"size": 3})]`, `[1, 1, 3]`},
+		{`let [a, b] = 1`, "ERROR: 1:5: cannot destructure INTEGER as ARRAY"},
+		{`let {a: [b]} = {"a": 1}`, "ERROR: 1:9: cannot destructure INTEGER as ARRAY"},
+		{`let {a} = [1]`, "ERROR: 1:5: cannot destructure ARRAY as HASH"},
+		{`let [a = b] = []`, "ERROR: 1:10: identifier not found: b"},
+		{`let f = fn([a]) { a }; f(1)`, "ERROR: 1:12: cannot destructure INTEGER as ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (2) { 1 => "one", 2 => "two", _ => "many" }`, `two`},
+		{`match (7) { 1 => "one", 2 => "two", _ => "many" }`, `many`},
+		{`match (-1) { -1 => "neg", _ => "other" }`, `neg`},
+		{`match (2.0) { 2 => "int", _ => "other" }`, `int`},
+		{`match ("b") { "a" => 1, "b" => 2 }`, `2`},
+		{`match (false) { true => 1, false => 0 }`, `0`},
+		{`match (5) { n => n * 2 }`, `10`},
+		{`match (5) { n when n > 10 => "big", n when n > 3 => "medium", _ => "small" }`, `medium`},
+		{`match ([1, 2]) { [] => "empty", [x] => "one", [x, y] => x + y }`, `3`},
+		{`match ([1, 2, 3]) { [x, y] => "two", [first, ...rest] => rest }`, `[2, 3]`},
+		{`match ([0, 5]) { [1, y] => "a", [0, y] => y }`, `5`},
+		{`match ([[1, 2], 3]) { [[a, b], c] => a + b + c }`, `6`},
+		{`match ("x") { [a] => 1, _ => 2 }`, `2`},
+		{`match ({"kind": "circle", "r": 2}) { {kind: "square", side} => side, {kind: "circle", r} => r * r }`, `4`},
+		{`match ({"a": 1}) { {b} => "b", {a, ...others} => [a, others] }`, `[1, {}]`},
+		{`match ({"a": 1}) { {} => "any hash" }`, `any hash`},
+		{`let classify = fn(code) { match (code) { c when c >= 500 => "server", c when c >= 400 => "client", _ => "ok" } }; map([200, 404, 503], classify)`, `[ok, client, server]`},
+		{`let x = 1; match (2) { x => x }; x`, `1`},
+		{`let n = 0; match (1) { _ => n += 1 }; n`, `1`},
+		{`match (3) { 1 => "one", 2 => "two" }`, "ERROR: 1:1: non-exhaustive match: no arm matches 3"},
+		{`match ([1, 2]) { [x] => x }`, "ERROR: 1:1: non-exhaustive match: no arm matches [1, 2]"},
+		{`match (1) { x when x + true => 1 }`, "ERROR: 1:22: type mismatch: INTEGER + BOOLEAN"},
+		{`match (y) { _ => 1 }`, "ERROR: 1:8: identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { 1 } catch (e) { 2 }`, `1`},
+		{`try { throw "boom"; 1 } catch (e) { e["message"] }`, `boom`},
+		{`try { throw "boom" } catch (e) { e["type"] }`, `Error`},
+		{`try { throw {"message": "bad id", "type": "ValueError", "id": 3} } catch (e) { [e["type"], e["message"], e["value"]["id"]] }`, `[ValueError, bad id, 3]`},
+		{`try { throw 42 } catch (e) { [e["message"], e["value"]] }`, `[42, 42]`},
+		{`try { 1 / 0 } catch (e) { [e["type"], e["message"], e["value"]] }`, `[RuntimeError, division by zero, null]`},
+		{`try { len(1) } catch (e) { e["message"] }`, "argument to `len` not supported, got INTEGER"},
+		{`try { missing } catch (e) { e["message"] }`, `identifier not found: missing`},
+		{`let f = fn() { throw "deep" }; let g = fn() { f() }; try { g() } catch (e) { e["stack"] }`, `[f (1:48), g (1:61)]`},
+		{`try { map([1], fn(x) { x / 0 }) } catch (e) { e["stack"] }`, `[map (1:10)]`},
+		{`let log = []; try { log = push(log, 1) } finally { log = push(log, 2) }; log`, `[1, 2]`},
+		{`let log = []; try { throw "x" } catch (e) { log = push(log, "c") } finally { log = push(log, "f") }; log`, `[c, f]`},
+		{`try { 1 } finally { 2 }`, `1`},
+		{`try { throw "a" } catch (e) { 1 } finally { throw "b" }`, "ERROR: 1:45: b"},
+		{`let f = fn() { try { return 1 } finally { 2 } }; f()`, `1`},
+		{`let f = fn() { try { return 1 } finally { return 2 } }; f()`, `2`},
+		{`try { try { throw "in" } catch (e) { throw "re:" + e["message"] } } catch (e) { e["message"] }`, `re:in`},
+		{`try { try { throw "in" } catch (e) { throw e } } catch (e) { [e["type"], e["message"]] }`, `[Error, in]`},
+		{`let results = map([1, 0, 2], fn(x) { try { 10 / x } catch (e) { -1 } }); results`, `[10, -1, 5]`},
+		{`let n = 0; for (x in [1, 2, 3]) { try { if (x == 2) { throw "skip" } n += x } catch (e) { continue } }; n`, `4`},
+		{`try { throw "x" } catch (e) { }`, `null`},
+		{`let e = 1; try { throw "x" } catch (e) { 2 }; e`, `1`},
+		{`throw "uncaught"`, "ERROR: 1:1: uncaught"},
+		{`try { throw "x" } finally { 1 }`, "ERROR: 1:7: x"},
+		{`throw y`, "ERROR: 1:7: identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestConstAndFreeze(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`const x = 5; x * 2`, `10`},
+		{`const [a, {b}] = [1, {"b": 2}]; a + b`, `3`},
+		{`const x = 1; let f = fn() { let x = 2; x += 1; x }; [f(), x]`, `[3, 1]`},
+		{`const x = 1; let f = fn(x) { x = 5; x }; [f(2), x]`, `[5, 1]`},
+		{`let x = 1; const x = 2; x`, `2`},
+		{`let i = 0; let sum = 0; while (i < 3) { const x = i * 2; sum += x; i = i + 1 }; sum`, `6`},
+		{`let i = 0; while (i < 2) { let seen = i; i += 1 }; seen`, "ERROR: 1:52: identifier not found: seen"},
+		{`let xs = freeze([1, [2, 3]]); xs[0] = 9`, "ERROR: 1:37: cannot modify frozen ARRAY"},
+		{`let xs = freeze([1, [2, 3]]); xs[1][0] = 9`, "ERROR: 1:40: cannot modify frozen ARRAY"},
+		{`let h = freeze({"a": {"b": [1]}}); h["a"]["b"][0] += 1`, "ERROR: 1:51: cannot modify frozen ARRAY"},
+		{`let h = freeze({"a": 1}); h["z"] = 2`, "ERROR: 1:34: cannot modify frozen HASH"},
+		{`let xs = freeze([1, 2]); let ys = push(xs, 3); ys[0] = 9; [xs, ys]`, `[[1, 2], [9, 2, 3]]`},
+		{`let h = freeze({"a": 1}); let m = merge(h, {"b": 2}); m["a"] = 0; [h, m]`, `[{a: 1}, {a: 0, b: 2}]`},
+		{`let xs = freeze([1, 2, 3]); let ys = xs[1:]; ys[0] = 0; ys`, `[0, 3]`},
+		{`let xs = [1]; freeze(xs); xs[0] = 2`, "ERROR: 1:33: cannot modify frozen ARRAY"},
+		{`let xs = [1]; let ys = [xs]; xs = push(xs, ys); freeze(xs); len(xs)`, `2`},
+		{`freeze(5)`, `5`},
+		{`freeze()`, "ERROR: 1:7: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHostConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`config["debug"]`, `false`},
+		{`config = {}`, "ERROR: 1:8: cannot assign to constant config"},
+		{`let config = {}`, "ERROR: 1:5: cannot redeclare constant config"},
+		{`let [a, config] = [1, 2]`, "ERROR: 1:9: cannot redeclare constant config"},
+		{`config["debug"] = true`, "ERROR: 1:17: cannot modify frozen HASH"},
+		{`let f = fn() { let config = 1; config += 1; config }; f()`, `2`},
+		{`let f = fn(config) { config }; f(3)`, `3`},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		config := &object.Hash{}
+		config.Set(&object.String{Value: "debug"}, &object.Boolean{Value: false})
+		env.SetConst("config", object.Freeze(config))
+
+		evaluated := Eval(parser.New(lexer.New(tt.input)).ParseProgram(), env)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong output for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestInternalErrorRecovery(t *testing.T) {
+	// A malformed AST that the parser never produces makes the evaluator panic.
+	program := &ast.Program{Statements: []ast.Statement{
+		&ast.ExpressionStatement{Expression: &ast.PrefixExpression{Operator: "-"}},
+	}}
+	evaluated := Eval(program, object.NewEnvironment())
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if !strings.HasPrefix(errObj.Message, "internal error: ") {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5 + true;", "ERROR: 1:3: type mismatch: INTEGER + BOOLEAN"},
+		{"let a = 1;\n  a + foobar", "ERROR: 2:7: identifier not found: foobar"},
+		{"let f = fn() { -true };\nf()", "ERROR: 1:16: unknown operator: -BOOLEAN"},
+		{`len(1)`, "ERROR: 1:4: argument to `len` not supported, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Inspect() != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expected, errObj.Inspect())
+		}
+	}
+}
+
 func testEval(input string) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
@@ -424,6 +1258,7 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	result, ok := obj.(*object.Integer)
 	if !ok {
 		t.Errorf("object is not Integer. got=%T (%+v)", obj, obj)
+		return false
 	}
 
 	if result.Value != expected {
@@ -433,10 +1268,39 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	return true
 }
 
+func testBigIntObject(t *testing.T, obj object.Object, expected string) bool {
+	result, ok := obj.(*object.BigInt)
+	if !ok {
+		t.Errorf("object is not BigInt. got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Inspect() != expected {
+		t.Errorf("object has wrong value. got=%s, want=%s", result.Inspect(), expected)
+		return false
+	}
+	return true
+}
+
+func testFloatObject(t *testing.T, obj object.Object, expected float64) bool {
+	result, ok := obj.(*object.Float)
+	if !ok {
+		t.Errorf("object is not Float. got=%T (%+v)", obj, obj)
+		return false
+	}
+
+	if result.Value != expected {
+		t.Errorf("object has wrong value. got=%g, want=%g", result.Value, expected)
+		return false
+	}
+	return true
+}
+
 func testBooleanObject(t *testing.T, obj object.Object, expected bool) bool {
 	result, ok := obj.(*object.Boolean)
 	if !ok {
 		t.Errorf("object is not Boolean. got=%T (%+v)", obj, obj)
+		return false
 	}
 	if result.Value != expected {
 		t.Errorf("object has wrong value. got=%t, want=%t", result.Value, expected)
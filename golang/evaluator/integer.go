@@ -0,0 +1,173 @@
+// Package evaluator contains the logic for evaluating the AST nodes.
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// maxBigIntBits bounds the size of BigInt results of ** and <<, so that a
+// script cannot exhaust the memory of the host process.
+const maxBigIntBits = 1 << 24
+
+// integerResult returns value as an Integer if the operation did not overflow.
+// Otherwise it redoes the operation with BigInt, or reports the overflow if
+// options enable checked arithmetic.
+func integerResult(value int64, ok bool, operator string, left, right object.Object, options object.Options) object.Object {
+	if ok {
+		return &object.Integer{Value: value}
+	}
+	if options.CheckedArithmetic {
+		return newError("integer overflow: %s %s %s", left.Inspect(), operator, right.Inspect())
+	}
+	return evalBigIntInfixExpression(operator, left, right)
+}
+
+func evalBigIntInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := toBigInt(left)
+	rightValue := toBigInt(right)
+
+	switch operator {
+	case "+":
+		return normalizeBigInt(new(big.Int).Add(leftValue, rightValue))
+	case "-":
+		return normalizeBigInt(new(big.Int).Sub(leftValue, rightValue))
+	case "*":
+		return normalizeBigInt(new(big.Int).Mul(leftValue, rightValue))
+	case "/":
+		if rightValue.Sign() == 0 {
+			return newError("division by zero")
+		}
+		return normalizeBigInt(new(big.Int).Quo(leftValue, rightValue))
+	case "%":
+		if rightValue.Sign() == 0 {
+			return newError("modulo by zero")
+		}
+		return normalizeBigInt(new(big.Int).Rem(leftValue, rightValue))
+	case "**":
+		if rightValue.Sign() < 0 {
+			return &object.Float{Value: math.Pow(toFloat(left), toFloat(right))}
+		}
+		if leftValue.BitLen() > 1 && (!rightValue.IsInt64() ||
+			int64(leftValue.BitLen()-1)*rightValue.Int64() > maxBigIntBits) {
+			return newError("integer too large: %s ** %s", left.Inspect(), right.Inspect())
+		}
+		return normalizeBigInt(new(big.Int).Exp(leftValue, rightValue, nil))
+	case "&":
+		return normalizeBigInt(new(big.Int).And(leftValue, rightValue))
+	case "|":
+		return normalizeBigInt(new(big.Int).Or(leftValue, rightValue))
+	case "^":
+		return normalizeBigInt(new(big.Int).Xor(leftValue, rightValue))
+	case "<<", ">>":
+		if rightValue.Sign() < 0 {
+			return newError("negative shift count: %s", right.Inspect())
+		}
+		if operator == ">>" {
+			if !rightValue.IsInt64() || rightValue.Int64() > int64(leftValue.BitLen()) {
+				rightValue = big.NewInt(int64(leftValue.BitLen()))
+			}
+			return normalizeBigInt(new(big.Int).Rsh(leftValue, uint(rightValue.Int64())))
+		}
+		if !rightValue.IsInt64() || int64(leftValue.BitLen())+rightValue.Int64() > maxBigIntBits {
+			return newError("integer too large: %s << %s", left.Inspect(), right.Inspect())
+		}
+		return normalizeBigInt(new(big.Int).Lsh(leftValue, uint(rightValue.Int64())))
+	case "<":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) != 0)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// normalizeBigInt returns x as an Integer if it fits in int64, or as a BigInt otherwise.
+func normalizeBigInt(x *big.Int) object.Object {
+	if x.IsInt64() {
+		return &object.Integer{Value: x.Int64()}
+	}
+	return &object.BigInt{Value: x}
+}
+
+// isInteger reports whether obj is an Integer or a BigInt.
+func isInteger(obj object.Object) bool {
+	t := obj.Type()
+	return t == object.IntegerObj || t == object.BigIntObj
+}
+
+// toBigInt converts an Integer or a BigInt to *big.Int. The result must not be modified.
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	default:
+		return new(big.Int)
+	}
+}
+
+// addInt64 returns a + b and whether the result did not overflow.
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (b >= 0) == (c >= a)
+}
+
+// subInt64 returns a - b and whether the result did not overflow.
+func subInt64(a, b int64) (int64, bool) {
+	c := a - b
+	return c, (b >= 0) == (c <= a)
+}
+
+// mulInt64 returns a * b and whether the result did not overflow.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return c, false
+	}
+	return c, c/b == a
+}
+
+// shlInt64 returns a shifted left by the non-negative count n and whether
+// the result did not overflow.
+func shlInt64(a, n int64) (int64, bool) {
+	if n >= 64 {
+		return 0, a == 0
+	}
+	c := a << n
+	return c, c>>n == a
+}
+
+// powInt64 returns base raised to the non-negative power exp by repeated
+// squaring and whether the result did not overflow.
+func powInt64(base, exp int64) (int64, bool) {
+	result := int64(1)
+	ok := true
+	for exp > 0 {
+		var fits bool
+		if exp&1 == 1 {
+			result, fits = mulInt64(result, base)
+			ok = ok && fits
+		}
+		exp >>= 1
+		if exp > 0 {
+			base, fits = mulInt64(base, base)
+			ok = ok && fits
+		}
+	}
+	return result, ok
+}
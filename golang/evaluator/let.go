@@ -0,0 +1,65 @@
+package evaluator
+
+import (
+	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/object"
+	"github.com/w40141/monkey-language/golang/token"
+)
+
+// evalLetStatement binds the name or pattern of a let or const statement to
+// its value in env. Neither may redeclare a constant of env; the parser
+// rejects the cases it can see, which leaves constants supplied by the host.
+func evalLetStatement(node *ast.LetStatement, env *object.Environment) object.Object {
+	names := []*ast.Identifier{node.Name}
+	if node.Pattern != nil {
+		names = patternNames(node.Pattern, nil)
+	}
+	for _, name := range names {
+		if env.Defines(name.Value) && env.IsConst(name.Value) {
+			return locate(newError("cannot redeclare constant %s", name.Value), name.Token.Start)
+		}
+	}
+
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+	if node.Pattern != nil {
+		if err := bindPattern(node.Pattern, val, env); err != nil {
+			return err
+		}
+	} else {
+		env.Set(node.Name.Value, val)
+	}
+
+	if node.Token.Type == token.CONST {
+		for _, name := range names {
+			obj, _ := env.Get(name.Value)
+			env.SetConst(name.Value, obj)
+		}
+	}
+	return val
+}
+
+// patternNames appends the names bound by pattern to names.
+func patternNames(pattern ast.Pattern, names []*ast.Identifier) []*ast.Identifier {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		names = append(names, pattern)
+	case *ast.ArrayPattern:
+		for _, el := range pattern.Elements {
+			names = patternNames(el.Target, names)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest)
+		}
+	case *ast.HashPattern:
+		for _, entry := range pattern.Entries {
+			names = patternNames(entry.Target, names)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest)
+		}
+	}
+	return names
+}
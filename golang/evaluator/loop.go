@@ -0,0 +1,85 @@
+package evaluator
+
+import (
+	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// evalLoopBody evaluates one iteration of a loop body. It reports whether
+// the loop should stop and, if the body returned or failed, the object to
+// pass on.
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (object.Object, bool) {
+	result := Eval(body, env)
+	if result == nil {
+		return nil, false
+	}
+	switch result.Type() {
+	case object.BreakObj:
+		return nil, true
+	case object.ReturnObj, object.ErrorObj:
+		return result, true
+	}
+	return nil, false
+}
+
+// evalWhileExpression runs the body as long as the condition is truthy. Each
+// iteration evaluates the body in a fresh environment, so bindings declared
+// in the body do not carry over to the next iteration.
+func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) object.Object {
+	for {
+		condition := Eval(we.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return nullObj
+		}
+		loopEnv := object.NewEnclosedEnvironment(env)
+		if result, stop := evalLoopBody(we.Body, loopEnv); stop {
+			if result != nil {
+				return result
+			}
+			return nullObj
+		}
+	}
+}
+
+// evalForExpression runs the body once for each element of an array, key of
+// a hash or character of a string. Assigning to an array element during the
+// loop is seen by later iterations; the set of keys of a hash is fixed when
+// the loop starts. Each iteration binds the loop variable in
+// a fresh environment, so closures created in the body capture their own value.
+func evalForExpression(fe *ast.ForExpression, env *object.Environment) object.Object {
+	iterable := Eval(fe.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	var elems []object.Object
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		elems = iterable.Elems
+	case *object.Hash:
+		for _, pair := range iterable.Pairs() {
+			elems = append(elems, pair.Key)
+		}
+	case *object.String:
+		for _, r := range iterable.Value {
+			elems = append(elems, &object.String{Value: string(r)})
+		}
+	default:
+		return locate(newError("not iterable: %s", iterable.Type()), fe.Iterable.Span().Start)
+	}
+
+	for _, elem := range elems {
+		loopEnv := object.NewEnclosedEnvironment(env)
+		loopEnv.Set(fe.Variable.Value, elem)
+		if result, stop := evalLoopBody(fe.Body, loopEnv); stop {
+			if result != nil {
+				return result
+			}
+			break
+		}
+	}
+	return nullObj
+}
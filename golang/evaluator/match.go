@@ -0,0 +1,95 @@
+package evaluator
+
+import (
+	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// evalMatchExpression evaluates the body of the first arm whose pattern
+// matches the subject and whose guard, if any, is truthy. The names bound by
+// the pattern are visible in the guard and the body only.
+func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range me.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+		matched, err := matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+	return newError("non-exhaustive match: no arm matches %s", subject.Inspect())
+}
+
+// matchPattern reports whether val has the shape of pattern and binds the
+// names of the pattern in env. Literals match equal values, _ matches
+// anything, array patterns match arrays of the same length (or at least as
+// long, with a rest element) and hash patterns match hashes that have all of
+// their keys.
+func matchPattern(pattern ast.Pattern, val object.Object, env *object.Environment) (bool, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			env.Set(pattern.Value, val)
+		}
+		return true, nil
+	case *ast.LiteralPattern:
+		lit := Eval(pattern.Value, env)
+		if isError(lit) {
+			return false, lit
+		}
+		return object.Equal(lit, val), nil
+	case *ast.ArrayPattern:
+		arr, ok := val.(*object.Array)
+		if !ok || len(arr.Elems) < len(pattern.Elements) ||
+			(pattern.Rest == nil && len(arr.Elems) != len(pattern.Elements)) {
+			return false, nil
+		}
+		for i, el := range pattern.Elements {
+			if matched, err := matchPattern(el.Target, arr.Elems[i], env); !matched || err != nil {
+				return false, err
+			}
+		}
+		if pattern.Rest != nil {
+			rest := append([]object.Object{}, arr.Elems[len(pattern.Elements):]...)
+			env.Set(pattern.Rest.Value, &object.Array{Elems: rest})
+		}
+		return true, nil
+	case *ast.HashPattern:
+		hash, ok := val.(*object.Hash)
+		if !ok {
+			return false, nil
+		}
+		for _, entry := range pattern.Entries {
+			pair, ok := hash.Get(&object.String{Value: entry.Key})
+			if !ok {
+				return false, nil
+			}
+			if matched, err := matchPattern(entry.Target, pair.Value, env); !matched || err != nil {
+				return false, err
+			}
+		}
+		if pattern.Rest != nil {
+			env.Set(pattern.Rest.Value, restOfHash(pattern, hash))
+		}
+		return true, nil
+	default:
+		return false, newError("unknown pattern: %s", pattern)
+	}
+}
@@ -0,0 +1,105 @@
+package evaluator
+
+import (
+	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// bindPattern binds the names in pattern to the matching parts of val in env.
+// An element or key missing from val takes the default of its pattern, or
+// null if it has none; extra elements and keys are ignored unless collected
+// by a rest element. It returns an error, or nil on success.
+func bindPattern(pattern ast.Pattern, val object.Object, env *object.Environment) object.Object {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		env.Set(pattern.Value, val)
+		return nil
+	case *ast.ArrayPattern:
+		return bindArrayPattern(pattern, val, env)
+	case *ast.HashPattern:
+		return bindHashPattern(pattern, val, env)
+	default:
+		return newError("cannot bind to %s", pattern)
+	}
+}
+
+func bindArrayPattern(pattern *ast.ArrayPattern, val object.Object, env *object.Environment) object.Object {
+	arr, ok := val.(*object.Array)
+	if !ok {
+		return locate(newError("cannot destructure %s as ARRAY", val.Type()), pattern.Token.Start)
+	}
+
+	for i, el := range pattern.Elements {
+		var elem object.Object
+		if i < len(arr.Elems) {
+			elem = arr.Elems[i]
+		}
+		if err := bindPatternElement(el, elem, env); err != nil {
+			return err
+		}
+	}
+	if pattern.Rest != nil {
+		rest := []object.Object{}
+		if len(arr.Elems) > len(pattern.Elements) {
+			rest = append(rest, arr.Elems[len(pattern.Elements):]...)
+		}
+		env.Set(pattern.Rest.Value, &object.Array{Elems: rest})
+	}
+	return nil
+}
+
+func bindHashPattern(pattern *ast.HashPattern, val object.Object, env *object.Environment) object.Object {
+	hash, ok := val.(*object.Hash)
+	if !ok {
+		return locate(newError("cannot destructure %s as HASH", val.Type()), pattern.Token.Start)
+	}
+
+	for _, entry := range pattern.Entries {
+		var value object.Object
+		if pair, ok := hash.Get(&object.String{Value: entry.Key}); ok {
+			value = pair.Value
+		}
+		if err := bindPatternElement(entry.PatternElement, value, env); err != nil {
+			return err
+		}
+	}
+	if pattern.Rest != nil {
+		env.Set(pattern.Rest.Value, restOfHash(pattern, hash))
+	}
+	return nil
+}
+
+// bindPatternElement binds el to val, or to its default if val is nil.
+func bindPatternElement(el ast.PatternElement, val object.Object, env *object.Environment) object.Object {
+	if val == nil {
+		val = nullObj
+		if el.Default != nil {
+			val = Eval(el.Default, env)
+			if isError(val) {
+				return val
+			}
+		}
+	}
+	return bindPattern(el.Target, val, env)
+}
+
+// restOfHash returns the pairs of hash whose keys are not named by pattern.
+func restOfHash(pattern *ast.HashPattern, hash *object.Hash) *object.Hash {
+	rest := &object.Hash{}
+	for _, pair := range hash.Pairs() {
+		if !hasPatternKey(pattern, pair.Key) {
+			rest.Set(pair.Key.(object.Hashable), pair.Value)
+		}
+	}
+	return rest
+}
+
+// hasPatternKey reports whether pattern names key in one of its entries.
+func hasPatternKey(pattern *ast.HashPattern, key object.Object) bool {
+	for _, entry := range pattern.Entries {
+		if object.Equal(&object.String{Value: entry.Key}, key) {
+			return true
+		}
+	}
+	return false
+}
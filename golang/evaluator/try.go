@@ -0,0 +1,116 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/object"
+)
+
+// runtimeErrorKind is the type reported for errors raised by the interpreter.
+const runtimeErrorKind = "RuntimeError"
+
+// evalThrowStatement raises the value as an error. A string becomes the
+// message; a hash may give the message and type under "message" and "type".
+func evalThrowStatement(ts *ast.ThrowStatement, env *object.Environment) object.Object {
+	val := Eval(ts.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	err := &object.Error{Message: val.Inspect(), Pos: ts.Token.Start, Kind: "Error", Value: val}
+	switch val := val.(type) {
+	case *object.String:
+		err.Message = val.Value
+	case *object.Hash:
+		if msg, ok := hashStringValue(val, "message"); ok {
+			err.Message = msg
+		}
+		if kind, ok := hashStringValue(val, "type"); ok {
+			err.Kind = kind
+		}
+	}
+	return err
+}
+
+// hashStringValue returns the string stored under key in hash.
+func hashStringValue(hash *object.Hash, key string) (string, bool) {
+	pair, ok := hash.Get(&object.String{Value: key})
+	if !ok {
+		return "", false
+	}
+	str, ok := pair.Value.(*object.String)
+	if !ok {
+		return "", false
+	}
+	return str.Value, true
+}
+
+// evalTryExpression evaluates the try block and, if it fails, the catch block
+// with the error bound to the catch parameter. The finally block always runs
+// afterwards; an error, return, break or continue from it replaces the result.
+func evalTryExpression(te *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(te.Block, env)
+	if err, ok := result.(*object.Error); ok && te.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		catchEnv.Set(te.CatchParam.Value, errorValue(err))
+		result = Eval(te.Catch, catchEnv)
+	}
+
+	if te.Finally != nil {
+		final := Eval(te.Finally, env)
+		if final != nil {
+			switch final.Type() {
+			case object.ErrorObj, object.ReturnObj, object.BreakObj, object.ContinueObj:
+				return final
+			}
+		}
+	}
+
+	if result == nil {
+		return nullObj
+	}
+	return result
+}
+
+// errorValue converts an error into the hash a catch block sees. It holds the
+// message, the type, the stack of calls the error propagated out of and the
+// thrown value, which is null for errors raised by the interpreter.
+func errorValue(err *object.Error) *object.Hash {
+	kind := err.Kind
+	if kind == "" {
+		kind = runtimeErrorKind
+	}
+	stack := make([]object.Object, len(err.Stack))
+	for i, frame := range err.Stack {
+		stack[i] = &object.String{Value: frame}
+	}
+	var value object.Object = nullObj
+	if err.Value != nil {
+		value = err.Value
+	}
+
+	hash := &object.Hash{}
+	hash.Set(&object.String{Value: "message"}, &object.String{Value: err.Message})
+	hash.Set(&object.String{Value: "type"}, &object.String{Value: kind})
+	hash.Set(&object.String{Value: "stack"}, &object.Array{Elems: stack})
+	hash.Set(&object.String{Value: "value"}, value)
+	return hash
+}
+
+// addCallFrame records on an error returned by a call that it propagated out
+// of the call.
+func addCallFrame(result object.Object, node *ast.CallExpression, fn object.Object) object.Object {
+	err, ok := result.(*object.Error)
+	if !ok {
+		return result
+	}
+	name := "anonymous function"
+	if f, ok := fn.(*object.Function); ok && f.Name != "" {
+		name = f.Name
+	} else if ident, ok := node.Function.(*ast.Identifier); ok {
+		name = ident.Value
+	}
+	err.Stack = append(err.Stack, fmt.Sprintf("%s (%s)", name, node.Token.Start))
+	return err
+}
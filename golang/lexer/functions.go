@@ -1,17 +1,78 @@
 // Package lexer implements a lexer for the Monkey programming language.
 package lexer
 
+import (
+	"unicode"
+
+	"github.com/w40141/monkey-language/golang/token"
+)
+
 // New returns a new instance of Lexer.
 func New(input string) *Lexer {
-	l := Lexer{input: input}
+	return NewFile("", input)
+}
+
+// NewFile returns a new instance of Lexer whose tokens are attributed to the given file name.
+func NewFile(filename string, input string) *Lexer {
+	l := Lexer{filename: filename, input: input, line: 1}
 	l.readChar()
 	return &l
 }
 
-func isLetter(ch byte) bool {
-	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z') || ch == '_'
+// isLetter reports whether ch may appear in an identifier.
+// Any Unicode letter is accepted.
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || ('a' <= ch && ch <= 'f') || ('A' <= ch && ch <= 'F')
+}
+
+// digitValue returns the numeric value of a hex digit, or 16 if ch is not one.
+func digitValue(ch rune) int {
+	switch {
+	case isDigit(ch):
+		return int(ch - '0')
+	case 'a' <= ch && ch <= 'f':
+		return int(ch-'a') + 10
+	case 'A' <= ch && ch <= 'F':
+		return int(ch-'A') + 10
+	default:
+		return 16
+	}
+}
+
+func isDigitByte(ch byte) bool {
+	return isDigit(rune(ch))
+}
+
+// isPrefixedDigitByte reports whether ch may be next to a '_' in a 0x, 0o or 0b literal.
+func isPrefixedDigitByte(ch byte) bool {
+	return isHexDigit(rune(ch)) || ch == 'x' || ch == 'X' || ch == 'o' || ch == 'O'
+}
+
+// misplacedSeparator returns the index of the first '_' in literal that is not
+// surrounded by digits, or -1 if every separator is well placed.
+func misplacedSeparator(literal string, isDigit func(byte) bool) int {
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '_' {
+			continue
+		}
+		if i == 0 || i == len(literal)-1 || !isDigit(literal[i-1]) || !isDigit(literal[i+1]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// offsetPosition returns pos moved n characters to the right on the same line.
+func offsetPosition(pos token.Position, n int) token.Position {
+	pos.Offset += n
+	pos.Column += n
+	return pos
+}
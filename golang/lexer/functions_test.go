@@ -16,7 +16,9 @@ func TestNew(t *testing.T) {
 				input:        "=",
 				position:     0,
 				readPosition: 1,
-				nowChar:           '=',
+				nowChar:      '=',
+				line:         1,
+				column:       1,
 			},
 		},
 		{
@@ -25,7 +27,9 @@ func TestNew(t *testing.T) {
 				input:        "",
 				position:     0,
 				readPosition: 1,
-				nowChar:           0,
+				nowChar:      0,
+				line:         1,
+				column:       1,
 			},
 		},
 	}
@@ -45,7 +49,7 @@ func TestNew(t *testing.T) {
 
 func TestIsLetter(t *testing.T) {
 	tests := []struct {
-		input byte
+		input rune
 		want  bool
 	}{
 		{'a', true},
@@ -53,7 +57,12 @@ func TestIsLetter(t *testing.T) {
 		{'z', true},
 		{'Z', true},
 		{'_', true},
+		{'é', true},
+		{'名', true},
+		{'あ', true},
 		{'1', false},
+		{'１', false},
+		{'!', false},
 	}
 
 	for i, tt := range tests {
@@ -70,7 +79,7 @@ func TestIsLetter(t *testing.T) {
 
 func TestIsDigit(t *testing.T) {
 	tests := []struct {
-		input byte
+		input rune
 		want  bool
 	}{
 		{'0', true},
@@ -2,35 +2,94 @@
 package lexer
 
 import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/w40141/monkey-language/golang/token"
 )
 
 // Lexer represents a lexer.
 type Lexer struct {
-	input string
-	// current position in input (points to current char)
+	filename string
+	input    string
+	// current byte offset in input (points to current char)
 	position int
-	// current reading position in input (after current char)
+	// current reading byte offset in input (after current char)
 	readPosition int
 	// current char under examination
-	nowChar byte
+	nowChar rune
+	// line of the current char, starting at 1
+	line int
+	// column of the current char in characters, starting at 1
+	column int
+	// errors found while reading ILLEGAL tokens
+	errors []string
+	// comments skipped so far, kept as trivia for tooling
+	comments []token.Token
+}
+
+// Errors returns the lexical errors found so far.
+func (l *Lexer) Errors() []string {
+	return l.errors
+}
+
+// Comments returns the comments skipped so far as COMMENT tokens.
+// The literal of each token includes its delimiters.
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
+}
+
+// errorAt records a lexical error at pos.
+func (l *Lexer) errorAt(pos token.Position, format string, a ...any) {
+	msg := fmt.Sprintf("%s: %s", pos, fmt.Sprintf(format, a...))
+	l.errors = append(l.errors, msg)
+}
+
+// illegal records a lexical error at pos and returns an ILLEGAL token for literal.
+func (l *Lexer) illegal(pos token.Position, literal string, format string, a ...any) token.Token {
+	l.errorAt(pos, format, a...)
+	return token.Token{Type: token.ILLEGAL, Literal: literal}
 }
 
 func (l *Lexer) readChar() {
+	if l.nowChar == '\n' {
+		l.line++
+		l.column = 0
+	}
+	width := 1
 	if l.readPosition >= len(l.input) {
 		// 0 means EOF
 		l.nowChar = 0
 	} else {
-		l.nowChar = l.input[l.readPosition]
+		l.nowChar, width = utf8.DecodeRuneInString(l.input[l.readPosition:])
 	}
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
+	l.column++
+}
+
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.position,
+		Line:     l.line,
+		Column:   l.column,
+	}
 }
 
 // NextToken returns the next token.
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
 	l.skipWhitespace()
+	start := l.currentPosition()
+	tok := l.readToken()
+	tok.Start = start
+	tok.End = l.currentPosition()
+	return tok
+}
+
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
 	switch l.nowChar {
 	case '=':
 		if l.peekChar() == '=' {
@@ -40,17 +99,29 @@ func (l *Lexer) NextToken() token.Token {
 				Type:    token.EQ,
 				Literal: string(ch) + string(l.nowChar),
 			}
+		} else if l.peekChar() == '>' {
+			tok = l.readTwoCharToken(token.ARROW)
 		} else {
 			tok = token.New(token.ASSIGN, l.nowChar)
 		}
 	case '+':
-		tok = token.New(token.PLUS, l.nowChar)
+		tok = l.readAssignment(token.New(token.PLUS, l.nowChar), token.PLUSASSIGN)
 	case '-':
-		tok = token.New(token.MINUS, l.nowChar)
+		tok = l.readAssignment(token.New(token.MINUS, l.nowChar), token.MINUSASSIGN)
 	case '*':
-		tok = token.New(token.TIMES, l.nowChar)
+		if l.peekChar() == '*' {
+			tok = l.readAssignment(l.readTwoCharToken(token.POWER), token.POWERASSIGN)
+		} else {
+			tok = l.readAssignment(token.New(token.TIMES, l.nowChar), token.TIMESASSIGN)
+		}
+	case '%':
+		tok = l.readAssignment(token.New(token.MODULO, l.nowChar), token.MODULOASSIGN)
+	case '^':
+		tok = l.readAssignment(token.New(token.BITXOR, l.nowChar), token.BITXORASSIGN)
+	case '~':
+		tok = token.New(token.BITNOT, l.nowChar)
 	case '/':
-		tok = token.New(token.DIVIDE, l.nowChar)
+		tok = l.readAssignment(token.New(token.DIVIDE, l.nowChar), token.DIVIDEASSIGN)
 	case '!':
 		if l.peekChar() == '=' {
 			ch := l.nowChar
@@ -68,6 +139,14 @@ func (l *Lexer) NextToken() token.Token {
 		tok = token.New(token.SEMICOLON, l.nowChar)
 	case ':':
 		tok = token.New(token.COLON, l.nowChar)
+	case '.':
+		if strings.HasPrefix(l.input[l.position:], token.ELLIPSIS) {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: token.ELLIPSIS}
+		} else {
+			tok = l.illegal(l.currentPosition(), string(l.nowChar), "illegal character %q", l.nowChar)
+		}
 	case '(':
 		tok = token.New(token.LPARAN, l.nowChar)
 	case ')':
@@ -81,12 +160,37 @@ func (l *Lexer) NextToken() token.Token {
 	case ']':
 		tok = token.New(token.RBRACKET, l.nowChar)
 	case '<':
-		tok = token.New(token.LT, l.nowChar)
+		switch l.peekChar() {
+		case '=':
+			tok = l.readTwoCharToken(token.LE)
+		case '<':
+			tok = l.readAssignment(l.readTwoCharToken(token.SHIFTLEFT), token.SHIFTLEFTASSIGN)
+		default:
+			tok = token.New(token.LT, l.nowChar)
+		}
 	case '>':
-		tok = token.New(token.GT, l.nowChar)
+		switch l.peekChar() {
+		case '=':
+			tok = l.readTwoCharToken(token.GE)
+		case '>':
+			tok = l.readAssignment(l.readTwoCharToken(token.SHIFTRIGHT), token.SHIFTRIGHTASSIGN)
+		default:
+			tok = token.New(token.GT, l.nowChar)
+		}
+	case '&':
+		if l.peekChar() == '&' {
+			tok = l.readTwoCharToken(token.AND)
+		} else {
+			tok = l.readAssignment(token.New(token.BITAND, l.nowChar), token.BITANDASSIGN)
+		}
+	case '|':
+		if l.peekChar() == '|' {
+			tok = l.readTwoCharToken(token.OR)
+		} else {
+			tok = l.readAssignment(token.New(token.BITOR, l.nowChar), token.BITORASSIGN)
+		}
 	case '"':
-		tok.Type = token.STRING
-		tok.Literal = l.readString()
+		tok = l.readString()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -96,17 +200,32 @@ func (l *Lexer) NextToken() token.Token {
 			tok.Type = token.LookupIdent(tok.Literal)
 			return tok
 		} else if isDigit(l.nowChar) {
-			tok.Literal = l.readNumber()
-			tok.Type = token.INT
-			return tok
+			return l.readNumber()
 		} else {
-			tok = token.New(token.ILLEGAL, l.nowChar)
+			tok = l.illegal(l.currentPosition(), string(l.nowChar), "illegal character %q", l.nowChar)
 		}
 	}
 	l.readChar()
 	return tok
 }
 
+// readTwoCharToken reads the current and the next char as a single token.
+func (l *Lexer) readTwoCharToken(tokenType token.Type) token.Token {
+	ch := l.nowChar
+	l.readChar()
+	return token.Token{Type: tokenType, Literal: string(ch) + string(l.nowChar)}
+}
+
+// readAssignment turns an operator token into its compound assignment form
+// when the next char is '='.
+func (l *Lexer) readAssignment(tok token.Token, assignType token.Type) token.Token {
+	if l.peekChar() != '=' {
+		return tok
+	}
+	l.readChar()
+	return token.Token{Type: assignType, Literal: tok.Literal + "="}
+}
+
 func (l *Lexer) readIdentifier() string {
 	position := l.position
 	nl := l
@@ -116,35 +235,241 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:nl.position]
 }
 
-func (l *Lexer) readNumber() string {
-	position := l.position
-	nl := l
-	for isDigit(nl.nowChar) {
+// readNumber reads an integer or a floating point literal such as 1_000, 0xFF, 3.14 or 1e-3.
+// A malformed literal yields an ILLEGAL token.
+func (l *Lexer) readNumber() token.Token {
+	start := l.currentPosition()
+	if l.nowChar == '0' {
+		switch l.peekChar() {
+		case 'x', 'X':
+			return l.readPrefixedInteger(start, 16, "hexadecimal")
+		case 'o', 'O':
+			return l.readPrefixedInteger(start, 8, "octal")
+		case 'b', 'B':
+			return l.readPrefixedInteger(start, 2, "binary")
+		}
+	}
+
+	tokenType := token.Type(token.INT)
+	l.readDigits()
+
+	if l.nowChar == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
 		l.readChar()
+		l.readDigits()
 	}
-	return l.input[position:nl.position]
+
+	if l.nowChar == 'e' || l.nowChar == 'E' {
+		tokenType = token.FLOAT
+		l.readChar()
+		if l.nowChar == '+' || l.nowChar == '-' {
+			l.readChar()
+		}
+		if !isDigit(l.nowChar) {
+			return l.illegal(start, l.input[start.Offset:l.position], "exponent has no digits")
+		}
+		l.readDigits()
+	}
+
+	literal := l.input[start.Offset:l.position]
+	if i := misplacedSeparator(literal, isDigitByte); i >= 0 {
+		return l.illegal(offsetPosition(start, i), literal, "'_' must separate successive digits")
+	}
+	return token.Token{Type: tokenType, Literal: literal}
+}
+
+// readPrefixedInteger reads an integer literal with a 0x, 0o or 0b prefix.
+func (l *Lexer) readPrefixedInteger(start token.Position, base int, name string) token.Token {
+	l.readChar()
+	l.readChar()
+	for isLetter(l.nowChar) || isDigit(l.nowChar) {
+		l.readChar()
+	}
+
+	literal := l.input[start.Offset:l.position]
+	digits := literal[2:]
+	if strings.Trim(digits, "_") == "" {
+		return l.illegal(start, literal, "%s literal has no digits", name)
+	}
+	for i, ch := range digits {
+		if ch != '_' && digitValue(ch) >= base {
+			return l.illegal(offsetPosition(start, i+2), literal, "invalid digit %q in %s literal", ch, name)
+		}
+	}
+	if i := misplacedSeparator(literal, isPrefixedDigitByte); i >= 0 {
+		return l.illegal(offsetPosition(start, i), literal, "'_' must separate successive digits")
+	}
+	return token.Token{Type: token.INT, Literal: literal}
 }
 
+// readDigits reads decimal digits and '_' separators.
+func (l *Lexer) readDigits() {
+	for isDigit(l.nowChar) || l.nowChar == '_' {
+		l.readChar()
+	}
+}
+
+// skipWhitespace skips whitespace and comments.
 func (l *Lexer) skipWhitespace() {
-	for l.nowChar == ' ' || l.nowChar == '\t' || l.nowChar == '\n' || l.nowChar == '\r' {
+	for {
+		switch {
+		case l.nowChar == ' ' || l.nowChar == '\t' || l.nowChar == '\n' || l.nowChar == '\r':
+			l.readChar()
+		case l.nowChar == '/' && l.peekChar() == '/':
+			l.readLineComment()
+		case l.nowChar == '/' && l.peekChar() == '*':
+			l.readBlockComment()
+		default:
+			return
+		}
+	}
+}
+
+// readLineComment reads a // comment up to, but not including, the end of the line.
+func (l *Lexer) readLineComment() {
+	start := l.currentPosition()
+	for l.nowChar != '\n' && l.nowChar != 0 {
 		l.readChar()
 	}
+	l.addComment(start)
 }
 
-func (l *Lexer) peekChar() byte {
+// readBlockComment reads a /* */ comment. Block comments may be nested.
+func (l *Lexer) readBlockComment() {
+	start := l.currentPosition()
+	depth := 0
+	for {
+		switch {
+		case l.nowChar == 0:
+			l.addComment(start)
+			l.errorAt(start, "unterminated block comment")
+			return
+		case l.nowChar == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+			l.readChar()
+		case l.nowChar == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+			l.readChar()
+			if depth == 0 {
+				l.addComment(start)
+				return
+			}
+		default:
+			l.readChar()
+		}
+	}
+}
+
+// addComment records the comment from start to the current position.
+func (l *Lexer) addComment(start token.Position) {
+	l.comments = append(l.comments, token.Token{
+		Type:    token.COMMENT,
+		Literal: l.input[start.Offset:l.position],
+		Start:   start,
+		End:     l.currentPosition(),
+	})
+}
+
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
-	return l.input[l.readPosition]
+	ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return ch
 }
 
-func (l *Lexer) readString() string {
-	position := l.position + 1
-	for {
+// readString reads a double-quoted string literal and decodes its escape sequences.
+// It leaves the lexer on the closing quote.
+func (l *Lexer) readString() token.Token {
+	start := l.currentPosition()
+	var out strings.Builder
+	var errPos token.Position
+	var errMsg string
+
+	l.readChar()
+	for l.nowChar != '"' {
+		if l.nowChar == 0 {
+			return l.illegal(start, l.input[start.Offset:l.position], "unterminated string literal")
+		}
+		if l.nowChar != '\\' {
+			// copy the raw bytes so that invalid UTF-8 is kept as is
+			out.WriteString(l.input[l.position:l.readPosition])
+			l.readChar()
+			continue
+		}
+		pos := l.currentPosition()
+		if msg := l.readEscape(&out); msg != "" && errMsg == "" {
+			errPos, errMsg = pos, msg
+		}
+	}
+
+	if errMsg != "" {
+		return l.illegal(errPos, l.input[start.Offset:l.position+1], "%s", errMsg)
+	}
+	return token.Token{Type: token.STRING, Literal: out.String()}
+}
+
+// readEscape decodes the escape sequence starting at the current backslash into out.
+// It returns a description of the problem if the sequence is invalid.
+func (l *Lexer) readEscape(out *strings.Builder) string {
+	l.readChar()
+	ch := l.nowChar
+	switch ch {
+	case 0:
+		return ""
+	case 'n':
+		out.WriteByte('\n')
+	case 't':
+		out.WriteByte('\t')
+	case 'r':
+		out.WriteByte('\r')
+	case '0':
+		out.WriteByte(0)
+	case '"', '\\':
+		out.WriteRune(ch)
+	case 'u':
 		l.readChar()
-		if l.nowChar == '"' || l.nowChar == 0 {
-			break
+		return l.readUnicodeEscape(out)
+	default:
+		if ch == '\n' {
+			return "unknown escape sequence at end of line"
 		}
+		l.readChar()
+		return fmt.Sprintf("unknown escape sequence \\%c", ch)
+	}
+	l.readChar()
+	return ""
+}
+
+// readUnicodeEscape decodes the {XXXX} part of a \u{XXXX} escape sequence into out.
+func (l *Lexer) readUnicodeEscape(out *strings.Builder) string {
+	if l.nowChar != '{' {
+		return "invalid unicode escape: expected { after \\u"
+	}
+	l.readChar()
+
+	position := l.position
+	for isHexDigit(l.nowChar) {
+		l.readChar()
+	}
+	digits := l.input[position:l.position]
+	if l.nowChar != '}' {
+		return "invalid unicode escape: expected hex digits followed by }"
+	}
+	l.readChar()
+
+	if len(digits) == 0 || len(digits) > 6 {
+		return fmt.Sprintf("invalid unicode escape \\u{%s}: expected 1 to 6 hex digits", digits)
+	}
+	var r rune
+	for _, d := range digits {
+		r = r<<4 | rune(digitValue(d))
+	}
+	if !utf8.ValidRune(r) {
+		return fmt.Sprintf("invalid unicode escape \\u{%s}: not a valid code point", digits)
 	}
-	return l.input[position:l.position]
+	out.WriteRune(r)
+	return ""
 }
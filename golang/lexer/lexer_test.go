@@ -18,12 +18,16 @@ func TestReadChar(t *testing.T) {
 				position:     0,
 				readPosition: 1,
 				nowChar:      '=',
+				line:         1,
+				column:       1,
 			},
 			want: Lexer{
 				input:        "=1",
 				position:     1,
 				readPosition: 2,
 				nowChar:      '1',
+				line:         1,
+				column:       2,
 			},
 		},
 		{
@@ -32,12 +36,16 @@ func TestReadChar(t *testing.T) {
 				position:     0,
 				readPosition: 1,
 				nowChar:      '=',
+				line:         1,
+				column:       1,
 			},
 			want: Lexer{
 				input:        "=",
 				position:     1,
 				readPosition: 2,
 				nowChar:      0,
+				line:         1,
+				column:       2,
 			},
 		},
 	}
@@ -76,6 +84,61 @@ func TestNextToken(t *testing.T) {
 				{token.EOF, ""},
 			},
 		},
+		{
+			input: `"a\nb" "tab\there" "say \"hi\"" "back\\slash" "\u{48}\u{1F600}"`,
+			wants: []want{
+				{token.STRING, "a\nb"},
+				{token.STRING, "tab\there"},
+				{token.STRING, `say "hi"`},
+				{token.STRING, `back\slash`},
+				{token.STRING, "H\U0001F600"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `
+			// leading comment
+			let x = 10 / 2; // trailing comment
+			/* block /* nested */ still comment */ x
+			`,
+			wants: []want{
+				{token.LET, "let"},
+				{token.IDENT, "x"},
+				{token.ASSIGN, "="},
+				{token.INT, "10"},
+				{token.DIVIDE, "/"},
+				{token.INT, "2"},
+				{token.SEMICOLON, ";"},
+				{token.IDENT, "x"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `let 名前 = "こんにちは、世界"; größe`,
+			wants: []want{
+				{token.LET, "let"},
+				{token.IDENT, "名前"},
+				{token.ASSIGN, "="},
+				{token.STRING, "こんにちは、世界"},
+				{token.SEMICOLON, ";"},
+				{token.IDENT, "größe"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `0xFF 0X1f 0o755 0b1010 1_000_000 0x_FF 1_000.5 007`,
+			wants: []want{
+				{token.INT, "0xFF"},
+				{token.INT, "0X1f"},
+				{token.INT, "0o755"},
+				{token.INT, "0b1010"},
+				{token.INT, "1_000_000"},
+				{token.INT, "0x_FF"},
+				{token.FLOAT, "1_000.5"},
+				{token.INT, "007"},
+				{token.EOF, ""},
+			},
+		},
 		{
 			input: `#`,
 			wants: []want{
@@ -183,10 +246,102 @@ func TestNextToken(t *testing.T) {
 			input: `
 			==
 			!=
+			<= >= && || < >
 			`,
 			wants: []want{
 				{token.EQ, "=="},
 				{token.NQ, "!="},
+				{token.LE, "<="},
+				{token.GE, ">="},
+				{token.AND, "&&"},
+				{token.OR, "||"},
+				{token.LT, "<"},
+				{token.GT, ">"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `% ** * & | ^ ~ << >>`,
+			wants: []want{
+				{token.MODULO, "%"},
+				{token.POWER, "**"},
+				{token.TIMES, "*"},
+				{token.BITAND, "&"},
+				{token.BITOR, "|"},
+				{token.BITXOR, "^"},
+				{token.BITNOT, "~"},
+				{token.SHIFTLEFT, "<<"},
+				{token.SHIFTRIGHT, ">>"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `x += 1; -= *= /= %= **= &= |= ^= <<= >>= = ==`,
+			wants: []want{
+				{token.IDENT, "x"},
+				{token.PLUSASSIGN, "+="},
+				{token.INT, "1"},
+				{token.SEMICOLON, ";"},
+				{token.MINUSASSIGN, "-="},
+				{token.TIMESASSIGN, "*="},
+				{token.DIVIDEASSIGN, "/="},
+				{token.MODULOASSIGN, "%="},
+				{token.POWERASSIGN, "**="},
+				{token.BITANDASSIGN, "&="},
+				{token.BITORASSIGN, "|="},
+				{token.BITXORASSIGN, "^="},
+				{token.SHIFTLEFTASSIGN, "<<="},
+				{token.SHIFTRIGHTASSIGN, ">>="},
+				{token.ASSIGN, "="},
+				{token.EQ, "=="},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `fn(...a) { f(...a) } ..`,
+			wants: []want{
+				{token.FUNCTION, "fn"},
+				{token.LPARAN, "("},
+				{token.ELLIPSIS, "..."},
+				{token.IDENT, "a"},
+				{token.RPARAN, ")"},
+				{token.LBRACE, "{"},
+				{token.IDENT, "f"},
+				{token.LPARAN, "("},
+				{token.ELLIPSIS, "..."},
+				{token.IDENT, "a"},
+				{token.RPARAN, ")"},
+				{token.RBRACE, "}"},
+				{token.ILLEGAL, "."},
+				{token.ILLEGAL, "."},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `match (x) { _ when y => 1 } >=`,
+			wants: []want{
+				{token.MATCH, "match"},
+				{token.LPARAN, "("},
+				{token.IDENT, "x"},
+				{token.RPARAN, ")"},
+				{token.LBRACE, "{"},
+				{token.IDENT, "_"},
+				{token.WHEN, "when"},
+				{token.IDENT, "y"},
+				{token.ARROW, "=>"},
+				{token.INT, "1"},
+				{token.RBRACE, "}"},
+				{token.GE, ">="},
+				{token.EOF, ""},
+			},
+		},
+		{
+			input: `const c = constant`,
+			wants: []want{
+				{token.CONST, "const"},
+				{token.IDENT, "c"},
+				{token.ASSIGN, "="},
+				{token.IDENT, "constant"},
 				{token.EOF, ""},
 			},
 		},
@@ -201,6 +356,19 @@ func TestNextToken(t *testing.T) {
 				{token.EOF, ""},
 			},
 		},
+		{
+			input: `3.14 1e3 2.5E-2 10 1.foo`,
+			wants: []want{
+				{token.FLOAT, "3.14"},
+				{token.FLOAT, "1e3"},
+				{token.FLOAT, "2.5E-2"},
+				{token.INT, "10"},
+				{token.INT, "1"},
+				{token.ILLEGAL, "."},
+				{token.IDENT, "foo"},
+				{token.EOF, ""},
+			},
+		},
 		{
 			input: `{"foo": "bar"}`,
 			wants: []want{
@@ -286,12 +454,16 @@ func TestSkipWhitespace(t *testing.T) {
 				position:     0,
 				readPosition: 1,
 				nowChar:      ' ',
+				line:         1,
+				column:       1,
 			},
 			want: Lexer{
 				input:        "  =",
 				position:     2,
 				readPosition: 3,
 				nowChar:      '=',
+				line:         1,
+				column:       3,
 			},
 		},
 		{
@@ -299,13 +471,17 @@ func TestSkipWhitespace(t *testing.T) {
 				input:        `	=`,
 				position:     0,
 				readPosition: 1,
-				nowChar:      byte('\t'),
+				nowChar:      '\t',
+				line:         1,
+				column:       1,
 			},
 			want: Lexer{
 				input:        `	=`,
 				position:     1,
 				readPosition: 2,
 				nowChar:      '=',
+				line:         1,
+				column:       2,
 			},
 		},
 		{
@@ -314,7 +490,9 @@ func TestSkipWhitespace(t *testing.T) {
 =`,
 				position:     0,
 				readPosition: 1,
-				nowChar:      byte('\n'),
+				nowChar:      '\n',
+				line:         1,
+				column:       1,
 			},
 			want: Lexer{
 				input: `
@@ -322,6 +500,8 @@ func TestSkipWhitespace(t *testing.T) {
 				position:     1,
 				readPosition: 2,
 				nowChar:      '=',
+				line:         2,
+				column:       1,
 			},
 		},
 		{
@@ -329,13 +509,17 @@ func TestSkipWhitespace(t *testing.T) {
 				input:        string(byte('\r')) + `=`,
 				position:     0,
 				readPosition: 1,
-				nowChar:      byte('\r'),
+				nowChar:      '\r',
+				line:         1,
+				column:       1,
 			},
 			want: Lexer{
 				input:        string(byte('\r')) + `=`,
 				position:     1,
 				readPosition: 2,
 				nowChar:      '=',
+				line:         1,
+				column:       2,
 			},
 		},
 	}
@@ -356,7 +540,7 @@ func TestSkipWhitespace(t *testing.T) {
 func TestPeekChar(t *testing.T) {
 	tests := []struct {
 		input Lexer
-		want  byte
+		want  rune
 	}{
 		{
 			input: Lexer{
@@ -397,3 +581,205 @@ func TestPeekChar(t *testing.T) {
 		}
 	}
 }
+
+func TestNextTokenPosition(t *testing.T) {
+	type want struct {
+		literal string
+		start   token.Position
+		end     token.Position
+	}
+
+	input := "let x = 5;\n  x + \"ab\""
+	wants := []want{
+		{"let", token.Position{Offset: 0, Line: 1, Column: 1}, token.Position{Offset: 3, Line: 1, Column: 4}},
+		{"x", token.Position{Offset: 4, Line: 1, Column: 5}, token.Position{Offset: 5, Line: 1, Column: 6}},
+		{"=", token.Position{Offset: 6, Line: 1, Column: 7}, token.Position{Offset: 7, Line: 1, Column: 8}},
+		{"5", token.Position{Offset: 8, Line: 1, Column: 9}, token.Position{Offset: 9, Line: 1, Column: 10}},
+		{";", token.Position{Offset: 9, Line: 1, Column: 10}, token.Position{Offset: 10, Line: 1, Column: 11}},
+		{"x", token.Position{Offset: 13, Line: 2, Column: 3}, token.Position{Offset: 14, Line: 2, Column: 4}},
+		{"+", token.Position{Offset: 15, Line: 2, Column: 5}, token.Position{Offset: 16, Line: 2, Column: 6}},
+		{"ab", token.Position{Offset: 17, Line: 2, Column: 7}, token.Position{Offset: 21, Line: 2, Column: 11}},
+	}
+
+	l := New(input)
+	for i, want := range wants {
+		tok := l.NextToken()
+		if tok.Literal != want.literal {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, want.literal, tok.Literal)
+		}
+		if tok.Start != want.start {
+			t.Fatalf("tests[%d] - start wrong. expected=%+v, got=%+v", i, want.start, tok.Start)
+		}
+		if tok.End != want.end {
+			t.Fatalf("tests[%d] - end wrong. expected=%+v, got=%+v", i, want.end, tok.End)
+		}
+	}
+}
+
+func TestNextTokenPositionUnicode(t *testing.T) {
+	l := New("\"日本語\" + 値 #")
+	wants := []token.Position{
+		{Offset: 0, Line: 1, Column: 1},
+		{Offset: 12, Line: 1, Column: 7},
+		{Offset: 14, Line: 1, Column: 9},
+		{Offset: 18, Line: 1, Column: 11},
+	}
+	for i, want := range wants {
+		tok := l.NextToken()
+		if tok.Start != want {
+			t.Fatalf("tests[%d] - start wrong. expected=%+v, got=%+v", i, want, tok.Start)
+		}
+	}
+
+	errors := l.Errors()
+	if len(errors) != 1 || errors[0] != "1:11: illegal character '#'" {
+		t.Fatalf("errors wrong. got=%q", errors)
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	l := NewFile("main.mk", "\n  foo")
+	tok := l.NextToken()
+	want := token.Position{Filename: "main.mk", Offset: 3, Line: 2, Column: 3}
+	if tok.Start != want {
+		t.Fatalf("start wrong. expected=%+v, got=%+v", want, tok.Start)
+	}
+}
+
+func TestReadStringErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		literal string
+		err     string
+	}{
+		{
+			input:   `"abc`,
+			literal: `"abc`,
+			err:     "1:1: unterminated string literal",
+		},
+		{
+			input:   `"a\qb"`,
+			literal: `"a\qb"`,
+			err:     "1:3: unknown escape sequence \\q",
+		},
+		{
+			input:   `"\u0041"`,
+			literal: `"\u0041"`,
+			err:     "1:2: invalid unicode escape: expected { after \\u",
+		},
+		{
+			input:   `"\u{110000}"`,
+			literal: `"\u{110000}"`,
+			err:     "1:2: invalid unicode escape \\u{110000}: not a valid code point",
+		},
+		{
+			input:   `"\u{}"`,
+			literal: `"\u{}"`,
+			err:     "1:2: invalid unicode escape \\u{}: expected 1 to 6 hex digits",
+		},
+		{
+			input:   `"ok\`,
+			literal: `"ok\`,
+			err:     "1:1: unterminated string literal",
+		},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("tests[%d] - type wrong. expected=%q, got=%q", i, token.ILLEGAL, tok.Type)
+		}
+		if tok.Literal != tt.literal {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.literal, tok.Literal)
+		}
+		errors := l.Errors()
+		if len(errors) != 1 || errors[0] != tt.err {
+			t.Fatalf("tests[%d] - errors wrong. expected=%q, got=%q", i, tt.err, errors)
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF after string. got=%+v", i, tok)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		input  string
+		wants  []string
+		errors []string
+	}{
+		{
+			input: "a // one\n// two\nb",
+			wants: []string{"// one", "// two"},
+		},
+		{
+			input: "a /* x /* y */ z */ b",
+			wants: []string{"/* x /* y */ z */"},
+		},
+		{
+			input: "a //",
+			wants: []string{"//"},
+		},
+		{
+			input:  "a /* x /* y */",
+			wants:  []string{"/* x /* y */"},
+			errors: []string{"1:3: unterminated block comment"},
+		},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+		comments := l.Comments()
+		if len(comments) != len(tt.wants) {
+			t.Fatalf("tests[%d] - comments wrong. expected=%q, got=%+v", i, tt.wants, comments)
+		}
+		for j, want := range tt.wants {
+			if comments[j].Type != token.COMMENT || comments[j].Literal != want {
+				t.Fatalf("tests[%d, %d] - comment wrong. expected=%q, got=%+v", i, j, want, comments[j])
+			}
+		}
+		if !reflect.DeepEqual(l.Errors(), tt.errors) {
+			t.Fatalf("tests[%d] - errors wrong. expected=%q, got=%q", i, tt.errors, l.Errors())
+		}
+	}
+}
+
+func TestReadNumberErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		literal string
+		err     string
+	}{
+		{input: "0x", literal: "0x", err: "1:1: hexadecimal literal has no digits"},
+		{input: "0b_", literal: "0b_", err: "1:1: binary literal has no digits"},
+		{input: "0b102", literal: "0b102", err: "1:5: invalid digit '2' in binary literal"},
+		{input: "0o78", literal: "0o78", err: "1:4: invalid digit '8' in octal literal"},
+		{input: "0xFG", literal: "0xFG", err: "1:4: invalid digit 'G' in hexadecimal literal"},
+		{input: "1__0", literal: "1__0", err: "1:2: '_' must separate successive digits"},
+		{input: "10_", literal: "10_", err: "1:3: '_' must separate successive digits"},
+		{input: "0xF_", literal: "0xF_", err: "1:4: '_' must separate successive digits"},
+		{input: "1_.5", literal: "1_.5", err: "1:2: '_' must separate successive digits"},
+		{input: "1e+", literal: "1e+", err: "1:1: exponent has no digits"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("tests[%d] - type wrong. expected=%q, got=%q", i, token.ILLEGAL, tok.Type)
+		}
+		if tok.Literal != tt.literal {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.literal, tok.Literal)
+		}
+		errors := l.Errors()
+		if len(errors) != 1 || errors[0] != tt.err {
+			t.Fatalf("tests[%d] - errors wrong. expected=%q, got=%q", i, tt.err, errors)
+		}
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF after number. got=%+v", i, tok)
+		}
+	}
+}
@@ -1,25 +1,46 @@
 // Package object defines the object system used in the Monkey programming language.
 package object
 
+// Options configures how programs are evaluated in an environment.
+type Options struct {
+	// CheckedArithmetic makes integer overflow an error instead of promoting
+	// the result to a BigInt.
+	CheckedArithmetic bool
+}
+
 // Environment represents the environment in which the Monkey programming language is evaluated.
 type Environment struct {
 	store map[string]Object
-	outer *Environment
+	// consts holds the names of the constant bindings in store.
+	consts  map[string]bool
+	outer   *Environment
+	options Options
 }
 
-// NewEnvironment creates a new environment.
+// NewEnvironment creates a new environment with the default options.
 func NewEnvironment() *Environment {
+	return NewEnvironmentWithOptions(Options{})
+}
+
+// NewEnvironmentWithOptions creates a new environment with the given options.
+func NewEnvironmentWithOptions(options Options) *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s}
+	return &Environment{store: s, options: options}
 }
 
-// NewEnclosedEnvironment creates a new environment enclosed in the given environment.
+// NewEnclosedEnvironment creates a new environment enclosed in the given
+// environment. It inherits the options of outer.
 func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
+	env := NewEnvironmentWithOptions(outer.options)
 	env.outer = outer
 	return env
 }
 
+// Options returns the options of the environment.
+func (e *Environment) Options() Options {
+	return e.options
+}
+
 // Get returns the object associated with the given name from the environment.
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
@@ -29,8 +50,51 @@ func (e *Environment) Get(name string) (Object, bool) {
 	return obj, ok
 }
 
+// Assign updates the binding of name in the nearest environment that defines
+// it. It reports false when no environment defines name.
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = val
+			return val, true
+		}
+	}
+	return nil, false
+}
+
 // Set sets the object associated with the given name in the environment.
+// It replaces a constant binding of name, if any, with a variable one.
 func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
+	delete(e.consts, name)
 	return val
 }
+
+// SetConst binds name to val in the environment as a constant. Scripts can
+// neither assign to a constant nor redeclare it in the same environment, so
+// a host can use it to supply values that scripts must not change.
+func (e *Environment) SetConst(name string, val Object) Object {
+	e.store[name] = val
+	if e.consts == nil {
+		e.consts = make(map[string]bool)
+	}
+	e.consts[name] = true
+	return val
+}
+
+// Defines reports whether name is bound in the environment itself rather
+// than in an enclosing one.
+func (e *Environment) Defines(name string) bool {
+	_, ok := e.store[name]
+	return ok
+}
+
+// IsConst reports whether the nearest binding of name is a constant.
+func (e *Environment) IsConst(name string) bool {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			return env.consts[name]
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// Package object defines the object system used in the Monkey programming language.
+package object
+
+import (
+	"math"
+	"math/big"
+)
+
+// Equal reports whether a and b are equal by value.
+// Numbers compare by numeric value across Integer, BigInt and Float.
+// Strings, arrays and hashes compare by content, recursing into nested values.
+// Any other object is only equal to itself.
+func Equal(a, b Object) bool {
+	return equal(a, b, map[[2]Object]bool{})
+}
+
+// equal compares a and b. visiting holds the pairs of containers being
+// compared further up the stack, so that cyclic values terminate.
+func equal(a, b Object, visiting map[[2]Object]bool) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	if isNumeric(a) && isNumeric(b) {
+		return numericEqual(a, b)
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a := a.(type) {
+	case *String:
+		return a.Value == b.(*String).Value
+	case *Boolean:
+		return a.Value == b.(*Boolean).Value
+	case *Null:
+		return true
+	case *Array:
+		b := b.(*Array)
+		if len(a.Elems) != len(b.Elems) {
+			return false
+		}
+		key := [2]Object{a, b}
+		if visiting[key] {
+			return true
+		}
+		visiting[key] = true
+		defer delete(visiting, key)
+		for i := range a.Elems {
+			if !equal(a.Elems[i], b.Elems[i], visiting) {
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		b := b.(*Hash)
+		if a.Len() != b.Len() {
+			return false
+		}
+		key := [2]Object{a, b}
+		if visiting[key] {
+			return true
+		}
+		visiting[key] = true
+		defer delete(visiting, key)
+		for _, pair := range a.Pairs() {
+			other, ok := b.Get(pair.Key.(Hashable))
+			if !ok || !equal(pair.Value, other.Value, visiting) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumeric(obj Object) bool {
+	switch obj.(type) {
+	case *Integer, *BigInt, *Float:
+		return true
+	default:
+		return false
+	}
+}
+
+// numericEqual compares two numeric objects by value.
+func numericEqual(a, b Object) bool {
+	c, ok := CompareNumbers(a, b)
+	return ok && c == 0
+}
+
+// CompareNumbers compares two Integer, BigInt or Float objects by value and
+// returns -1, 0 or +1. Integers are compared with floats exactly rather than
+// after converting them to float64. It reports false if either value is NaN.
+func CompareNumbers(a, b Object) (int, bool) {
+	fa, aIsFloat := a.(*Float)
+	fb, bIsFloat := b.(*Float)
+	if (aIsFloat && math.IsNaN(fa.Value)) || (bIsFloat && math.IsNaN(fb.Value)) {
+		return 0, false
+	}
+	if !aIsFloat && !bIsFloat {
+		return toBig(a).Cmp(toBig(b)), true
+	}
+	return toBigFloat(a).Cmp(toBigFloat(b)), true
+}
+
+// toBigFloat converts a numeric object other than NaN to *big.Float exactly.
+func toBigFloat(obj Object) *big.Float {
+	if f, ok := obj.(*Float); ok {
+		return big.NewFloat(f.Value)
+	}
+	return new(big.Float).SetInt(toBig(obj))
+}
+
+func toBig(obj Object) *big.Int {
+	switch obj := obj.(type) {
+	case *Integer:
+		return big.NewInt(obj.Value)
+	case *BigInt:
+		return obj.Value
+	default:
+		return new(big.Int)
+	}
+}
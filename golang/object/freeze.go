@@ -0,0 +1,26 @@
+// Package object defines the object system used in the Monkey programming language.
+package object
+
+// Freeze makes obj and every array and hash reachable from it read-only and
+// returns obj. Other objects are immutable already and are returned as is.
+func Freeze(obj Object) Object {
+	switch obj := obj.(type) {
+	case *Array:
+		if obj.Frozen {
+			return obj
+		}
+		obj.Frozen = true
+		for _, elem := range obj.Elems {
+			Freeze(elem)
+		}
+	case *Hash:
+		if obj.Frozen {
+			return obj
+		}
+		obj.Frozen = true
+		for _, pair := range obj.entries {
+			Freeze(pair.Value)
+		}
+	}
+	return obj
+}
@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/w40141/monkey-language/golang/ast"
+	"github.com/w40141/monkey-language/golang/token"
 )
 
 type (
@@ -20,12 +23,20 @@ type (
 const (
 	// IntegerObj represents the type of an integer object.
 	IntegerObj = "INTEGER"
+	// BigIntObj represents the type of an arbitrary-precision integer object.
+	BigIntObj = "BIGINT"
+	// FloatObj represents the type of a floating point object.
+	FloatObj = "FLOAT"
 	// BooleanObj represents the type of a boolean object.
 	BooleanObj = "BOOLEAN"
 	// NullObj represents the type of a null object.
 	NullObj = "NULL"
 	// ReturnObj represents the type of a return object.
 	ReturnObj = "RETURN"
+	// BreakObj represents the type of a break object.
+	BreakObj = "BREAK"
+	// ContinueObj represents the type of a continue object.
+	ContinueObj = "CONTINUE"
 	// ErrorObj represents the type of an error object.
 	ErrorObj = "ERROR"
 	// FunctionObj represents the type of a function object.
@@ -63,6 +74,47 @@ func (i *Integer) Inspect() string {
 	return fmt.Sprintf("%d", i.Value)
 }
 
+var _ Object = (*BigInt)(nil)
+
+// BigInt represents an integer that does not fit in an Integer.
+// Arithmetic produces a BigInt only when the result overflows int64,
+// so a BigInt never holds a value that an Integer could represent.
+type BigInt struct {
+	Value *big.Int
+}
+
+// Type returns the type of the big integer object.
+func (b *BigInt) Type() Type {
+	return BigIntObj
+}
+
+// Inspect returns the string representation of the big integer object.
+func (b *BigInt) Inspect() string {
+	return b.Value.String()
+}
+
+var _ Object = (*Float)(nil)
+
+// Float represents a floating point object in the Monkey programming language.
+type Float struct {
+	Value float64
+}
+
+// Type returns the type of the float object.
+func (f *Float) Type() Type {
+	return FloatObj
+}
+
+// Inspect returns the string representation of the float object.
+// Integral values keep a trailing ".0" so that they are distinguishable from integers.
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if strings.ContainsAny(s, ".eIN") {
+		return s
+	}
+	return s + ".0"
+}
+
 var _ Object = (*Boolean)(nil)
 
 // Boolean represents a boolean object in the Monkey programming language.
@@ -112,11 +164,51 @@ func (r *Return) Inspect() string {
 	return r.Value.Inspect()
 }
 
+var _ Object = (*Break)(nil)
+
+// Break signals a break statement to the enclosing loop.
+type Break struct{}
+
+// Type returns the type of the break object.
+func (b *Break) Type() Type {
+	return BreakObj
+}
+
+// Inspect returns the string representation of the break object.
+func (b *Break) Inspect() string {
+	return "break"
+}
+
+var _ Object = (*Continue)(nil)
+
+// Continue signals a continue statement to the enclosing loop.
+type Continue struct{}
+
+// Type returns the type of the continue object.
+func (c *Continue) Type() Type {
+	return ContinueObj
+}
+
+// Inspect returns the string representation of the continue object.
+func (c *Continue) Inspect() string {
+	return "continue"
+}
+
 var _ Object = (*Error)(nil)
 
 // Error represents an error object in the Monkey programming language.
 type Error struct {
 	Message string
+	// Pos is the location of the expression that raised the error, if known.
+	Pos token.Position
+	// Kind classifies the error. It is empty for errors raised by the
+	// interpreter itself.
+	Kind string
+	// Value is the value given to throw, or nil for errors raised by the
+	// interpreter itself.
+	Value Object
+	// Stack lists the calls the error propagated out of, innermost first.
+	Stack []string
 }
 
 // Type returns the type of the error object.
@@ -126,6 +218,9 @@ func (e *Error) Type() Type {
 
 // Inspect returns the string representation of the error object.
 func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return "ERROR: " + e.Pos.String() + ": " + e.Message
+	}
 	return "ERROR: " + e.Message
 }
 
@@ -133,9 +228,15 @@ var _ Object = (*Function)(nil)
 
 // Function represents a function object in the Monkey programming language.
 type Function struct {
-	Parameters []*ast.Identifier
-	Body       *ast.BlockStatement
-	Env        *Environment
+	Parameters []ast.Pattern
+	// Defaults holds the default value of each parameter, or nil if it has none.
+	Defaults []ast.Expression
+	// Rest is the parameter collecting the remaining arguments, if any.
+	Rest *ast.Identifier
+	Body *ast.BlockStatement
+	Env  *Environment
+	// Name is the name the function was defined with, or "" if it is anonymous.
+	Name string
 }
 
 // Type returns the type of the function object.
@@ -147,14 +248,9 @@ func (f *Function) Type() Type {
 func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
-	}
-
 	out.WriteString("fn")
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(ast.ParameterList(f.Parameters, f.Defaults, f.Rest))
 	out.WriteString(") {\n")
 	out.WriteString(f.Body.String())
 	out.WriteString("\n}")
@@ -201,15 +297,30 @@ var _ Object = (*Array)(nil)
 // Array represents an array object in the Monkey programming language.
 type Array struct {
 	Elems []Object
+	// Frozen reports whether the elements may no longer be assigned.
+	Frozen bool
 }
 
 // Inspect implements Object.
 func (a *Array) Inspect() string {
+	return a.inspect(map[Object]bool{})
+}
+
+// inspect returns the string representation of the array. visiting holds the
+// containers being printed further up the stack; an array that contains
+// itself prints as [...] where it repeats.
+func (a *Array) inspect(visiting map[Object]bool) string {
+	if visiting[a] {
+		return "[...]"
+	}
+	visiting[a] = true
+	defer delete(visiting, a)
+
 	var out bytes.Buffer
 
 	elems := []string{}
 	for _, e := range a.Elems {
-		elems = append(elems, e.Inspect())
+		elems = append(elems, inspect(e, visiting))
 	}
 
 	out.WriteString("[")
@@ -233,17 +344,79 @@ type HashPair struct {
 var _ Object = (*Hash)(nil)
 
 // Hash represents a hash object in the Monkey programming language.
+// Pairs are kept in insertion order. The zero value is an empty hash.
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	// entries holds the pairs in insertion order.
+	entries []HashPair
+	// buckets maps the hash key of a key to the indexes of its entries. Keys
+	// whose hash keys collide share a bucket and are told apart with Equal.
+	buckets map[HashKey][]int
+	// Frozen reports whether the pairs may no longer be assigned.
+	Frozen bool
+}
+
+// find returns the index of the entry whose key equals key, or -1.
+func (h *Hash) find(key Hashable) int {
+	for _, i := range h.buckets[key.HashKey()] {
+		if Equal(h.entries[i].Key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the pair whose key equals key.
+func (h *Hash) Get(key Hashable) (HashPair, bool) {
+	if i := h.find(key); i >= 0 {
+		return h.entries[i], true
+	}
+	return HashPair{}, false
+}
+
+// Set stores value under key. An equal key keeps its position and gets the
+// new value; a new key is appended.
+func (h *Hash) Set(key Hashable, value Object) {
+	if i := h.find(key); i >= 0 {
+		h.entries[i].Value = value
+		return
+	}
+	if h.buckets == nil {
+		h.buckets = make(map[HashKey][]int)
+	}
+	hashKey := key.HashKey()
+	h.buckets[hashKey] = append(h.buckets[hashKey], len(h.entries))
+	h.entries = append(h.entries, HashPair{Key: key, Value: value})
+}
+
+// Len returns the number of pairs in the hash.
+func (h *Hash) Len() int {
+	return len(h.entries)
+}
+
+// Pairs returns a copy of the pairs of the hash in insertion order.
+func (h *Hash) Pairs() []HashPair {
+	return append([]HashPair(nil), h.entries...)
 }
 
 // Inspect implements Object.
 func (h *Hash) Inspect() string {
+	return h.inspect(map[Object]bool{})
+}
+
+// inspect returns the string representation of the hash. A hash that
+// contains itself prints as {...} where it repeats.
+func (h *Hash) inspect(visiting map[Object]bool) string {
+	if visiting[h] {
+		return "{...}"
+	}
+	visiting[h] = true
+	defer delete(visiting, h)
+
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for _, pair := range h.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	for _, pair := range h.Pairs() {
+		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), inspect(pair.Value, visiting)))
 	}
 
 	out.WriteString("{")
@@ -258,8 +431,22 @@ func (h *Hash) Type() Type {
 	return HashObj
 }
 
-// Hashable represents
+// inspect returns the string representation of obj, passing visiting on to
+// arrays and hashes so that cyclic values terminate.
+func inspect(obj Object, visiting map[Object]bool) string {
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.inspect(visiting)
+	case *Hash:
+		return obj.inspect(visiting)
+	default:
+		return obj.Inspect()
+	}
+}
+
+// Hashable is an object that can be used as a hash key.
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
 
@@ -285,9 +472,26 @@ func (i Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+// HashKey represents a hash key in the Monkey programming language.
+// A BigInt that fits in int64 hashes like the equal Integer.
+func (b BigInt) HashKey() HashKey {
+	if b.Value.IsInt64() {
+		return Integer{Value: b.Value.Int64()}.HashKey()
+	}
+	h := fnv.New64a()
+	h.Write([]byte{byte(b.Value.Sign() + 1)})
+	h.Write(b.Value.Bytes())
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
 // HashKey represents a hash key in the Monkey programming language.
 func (s String) HashKey() HashKey {
+	return HashKey{Type: s.Type(), Value: hashString(s.Value)}
+}
+
+// hashString is the hash function for string keys. Tests replace it to force collisions.
+var hashString = func(s string) uint64 {
 	h := fnv.New64a()
-	h.Write([]byte(s.Value))
-	return HashKey{Type: s.Type(), Value: h.Sum64()}
+	h.Write([]byte(s))
+	return h.Sum64()
 }
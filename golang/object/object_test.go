@@ -1,6 +1,10 @@
 package object
 
-import "testing"
+import (
+	"math"
+	"math/big"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -20,3 +24,214 @@ func TestStringHashKey(t *testing.T) {
 		t.Error("strings with different content have same has keys")
 	}
 }
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{2, "2.0"},
+		{3.25, "3.25"},
+		{-0.5, "-0.5"},
+		{1e21, "1e+21"},
+	}
+
+	for _, tt := range tests {
+		if got := (&Float{Value: tt.input}).Inspect(); got != tt.want {
+			t.Errorf("Inspect() wrong. expected=%q, got=%q", tt.want, got)
+		}
+	}
+}
+
+func TestBigIntHashKey(t *testing.T) {
+	big1 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 70)}
+	big2 := &BigInt{Value: new(big.Int).Lsh(big.NewInt(1), 70)}
+	negative := &BigInt{Value: new(big.Int).Neg(big1.Value)}
+	small := &BigInt{Value: big.NewInt(42)}
+
+	if big1.HashKey() != big2.HashKey() {
+		t.Error("big integers with same value have different hash keys")
+	}
+
+	if big1.HashKey() == negative.HashKey() {
+		t.Error("big integers with different signs have same hash keys")
+	}
+
+	if small.HashKey() != (&Integer{Value: 42}).HashKey() {
+		t.Error("big integer and integer with same value have different hash keys")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	cyclic1 := &Array{}
+	cyclic1.Elems = []Object{&Integer{Value: 1}, cyclic1}
+	cyclic2 := &Array{}
+	cyclic2.Elems = []Object{&Integer{Value: 1}, cyclic2}
+
+	tests := []struct {
+		name string
+		a    Object
+		b    Object
+		want bool
+	}{
+		{"same strings", &String{Value: "a"}, &String{Value: "a"}, true},
+		{"different strings", &String{Value: "a"}, &String{Value: "b"}, false},
+		{"integer and float", &Integer{Value: 2}, &Float{Value: 2}, true},
+		{"integer and fractional float", &Integer{Value: 2}, &Float{Value: 2.5}, false},
+		{"integer and big integer", &Integer{Value: 2}, &BigInt{Value: big.NewInt(2)}, true},
+		{"big integer and NaN", &BigInt{Value: big.NewInt(0)}, &Float{Value: math.NaN()}, false},
+		{"integer and nearest float", &Integer{Value: 1<<53 + 1}, &Float{Value: 1 << 53}, false},
+		{"integer and infinity", &Integer{Value: math.MaxInt64}, &Float{Value: math.Inf(1)}, false},
+		{"NaNs", &Float{Value: math.NaN()}, &Float{Value: math.NaN()}, false},
+		{"booleans", &Boolean{Value: true}, &Boolean{Value: true}, true},
+		{"nulls", &Null{}, &Null{}, true},
+		{"string and integer", &String{Value: "1"}, &Integer{Value: 1}, false},
+		{
+			"nested arrays",
+			&Array{Elems: []Object{&Array{Elems: []Object{&String{Value: "x"}}}}},
+			&Array{Elems: []Object{&Array{Elems: []Object{&String{Value: "x"}}}}},
+			true,
+		},
+		{"cyclic arrays", cyclic1, cyclic2, true},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.want {
+			t.Errorf("name: %s - Equal() wrong. expected=%t, got=%t", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestHashCollisions(t *testing.T) {
+	original := hashString
+	hashString = func(string) uint64 { return 42 }
+	defer func() { hashString = original }()
+
+	a := &String{Value: "a"}
+	b := &String{Value: "b"}
+	if a.HashKey() != b.HashKey() {
+		t.Fatal("hash keys do not collide")
+	}
+
+	h := &Hash{}
+	h.Set(a, &Integer{Value: 1})
+	h.Set(b, &Integer{Value: 2})
+	h.Set(&String{Value: "a"}, &Integer{Value: 3})
+
+	if h.Len() != 2 {
+		t.Fatalf("hash has wrong num of pairs. got=%d", h.Len())
+	}
+
+	tests := []struct {
+		key  Hashable
+		want int64
+	}{
+		{&String{Value: "a"}, 3},
+		{&String{Value: "b"}, 2},
+	}
+	for _, tt := range tests {
+		pair, ok := h.Get(tt.key)
+		if !ok {
+			t.Fatalf("no pair for key %q", tt.key.Inspect())
+		}
+		if got := pair.Value.(*Integer).Value; got != tt.want {
+			t.Errorf("wrong value for key %q. expected=%d, got=%d", tt.key.Inspect(), tt.want, got)
+		}
+	}
+
+	if _, ok := h.Get(&String{Value: "c"}); ok {
+		t.Error("found a pair for a colliding key that was never set")
+	}
+
+	other := &Hash{}
+	other.Set(&String{Value: "b"}, &Integer{Value: 2})
+	other.Set(&String{Value: "a"}, &Integer{Value: 3})
+	if !Equal(h, other) {
+		t.Error("hashes with colliding keys and same content are not equal")
+	}
+}
+
+func TestHashInspectOrder(t *testing.T) {
+	h := &Hash{}
+	h.Set(&String{Value: "z"}, &Integer{Value: 1})
+	h.Set(&Integer{Value: 2}, &Integer{Value: 2})
+	h.Set(&Boolean{Value: true}, &Integer{Value: 3})
+	h.Set(&String{Value: "z"}, &Integer{Value: 4})
+
+	want := `{z: 4, 2: 2, true: 3}`
+	for i := 0; i < 10; i++ {
+		if got := h.Inspect(); got != want {
+			t.Fatalf("Inspect() wrong. expected=%q, got=%q", want, got)
+		}
+	}
+}
+
+func TestEnvironmentConst(t *testing.T) {
+	outer := NewEnvironment()
+	outer.SetConst("x", &Integer{Value: 1})
+	inner := NewEnclosedEnvironment(outer)
+
+	if !inner.IsConst("x") || inner.Defines("x") {
+		t.Error("inner environment does not see the outer constant x")
+	}
+	inner.Set("x", &Integer{Value: 2})
+	if inner.IsConst("x") || !outer.IsConst("x") {
+		t.Error("shadowing x in the inner environment changed its constness")
+	}
+	outer.Set("x", &Integer{Value: 3})
+	if outer.IsConst("x") {
+		t.Error("Set did not replace the constant x")
+	}
+	if inner.IsConst("y") {
+		t.Error("undefined name y is constant")
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	inner := &Array{Elems: []Object{&Integer{Value: 1}}}
+	hash := &Hash{}
+	hash.Set(&String{Value: "a"}, inner)
+	outer := &Array{Elems: []Object{hash, &String{Value: "s"}}}
+	inner.Elems = append(inner.Elems, outer)
+
+	if got := Freeze(outer); got != outer {
+		t.Errorf("Freeze returned a different object. got=%v", got)
+	}
+	if !outer.Frozen || !hash.Frozen || !inner.Frozen {
+		t.Errorf("values not frozen deeply. outer=%t, hash=%t, inner=%t", outer.Frozen, hash.Frozen, inner.Frozen)
+	}
+}
+
+func TestEnvironmentOptions(t *testing.T) {
+	outer := NewEnvironmentWithOptions(Options{CheckedArithmetic: true})
+	inner := NewEnclosedEnvironment(NewEnclosedEnvironment(outer))
+	if !inner.Options().CheckedArithmetic {
+		t.Error("enclosed environment did not inherit the options")
+	}
+	if NewEnvironment().Options().CheckedArithmetic {
+		t.Error("checked arithmetic enabled by default")
+	}
+}
+
+func TestEnvironmentAssign(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("x", &Integer{Value: 1})
+	inner := NewEnclosedEnvironment(outer)
+	inner.Set("y", &Integer{Value: 2})
+
+	if _, ok := inner.Assign("x", &Integer{Value: 3}); !ok {
+		t.Fatal("Assign did not find x")
+	}
+	if obj, _ := outer.Get("x"); obj.(*Integer).Value != 3 {
+		t.Errorf("outer x not updated. got=%d", obj.(*Integer).Value)
+	}
+	if _, ok := inner.store["x"]; ok {
+		t.Error("Assign created a binding in the inner environment")
+	}
+	if _, ok := outer.Assign("y", &Integer{Value: 4}); ok {
+		t.Error("Assign updated a binding of an inner environment")
+	}
+	if _, ok := inner.Assign("z", &Integer{Value: 5}); ok {
+		t.Error("Assign found an undefined name")
+	}
+}
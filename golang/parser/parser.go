@@ -2,7 +2,9 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/w40141/monkey-language/golang/ast"
@@ -19,16 +21,33 @@ const (
 	_ int = iota
 	// LOWEST is the lowest precedence.
 	LOWEST
+	// ASSIGN is the precedence for the = operator and its compound forms.
+	ASSIGN
+	// LOGICALOR is the precedence for the || operator.
+	LOGICALOR
+	// LOGICALAND is the precedence for the && operator.
+	LOGICALAND
 	// EQUALS is the precedence for the == operator.
 	EQUALS
-	// LESSGREATER is the precedence for the < or > operators.
+	// LESSGREATER is the precedence for the <, >, <= or >= operators.
 	LESSGREATER
+	// BITWISEOR is the precedence for the | operator.
+	BITWISEOR
+	// BITWISEXOR is the precedence for the ^ operator.
+	BITWISEXOR
+	// BITWISEAND is the precedence for the & operator.
+	BITWISEAND
+	// SHIFT is the precedence for the << or >> operators.
+	SHIFT
 	// SUM is the precedence for the + operator.
 	SUM
-	// PRODUCT is the precedence for the * operator.
+	// PRODUCT is the precedence for the *, / or % operators.
 	PRODUCT
-	// PREFIX is the precedence for the -X or !X operators.
+	// PREFIX is the precedence for the -X, !X or ~X operators.
 	PREFIX
+	// POWER is the precedence for the ** operator. It binds tighter than
+	// prefix operators, so -2 ** 2 is -(2 ** 2).
+	POWER
 	// CALL is the precedence for the myFunction(X) operator.
 	CALL
 	// INDEX is the precedence for the array[index] operator.
@@ -36,16 +55,39 @@ const (
 )
 
 var precedences = map[token.Type]int{
-	token.EQ:       EQUALS,
-	token.NQ:       EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.DIVIDE:   PRODUCT,
-	token.TIMES:    PRODUCT,
-	token.LPARAN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:           ASSIGN,
+	token.PLUSASSIGN:       ASSIGN,
+	token.MINUSASSIGN:      ASSIGN,
+	token.TIMESASSIGN:      ASSIGN,
+	token.DIVIDEASSIGN:     ASSIGN,
+	token.MODULOASSIGN:     ASSIGN,
+	token.POWERASSIGN:      ASSIGN,
+	token.BITANDASSIGN:     ASSIGN,
+	token.BITORASSIGN:      ASSIGN,
+	token.BITXORASSIGN:     ASSIGN,
+	token.SHIFTLEFTASSIGN:  ASSIGN,
+	token.SHIFTRIGHTASSIGN: ASSIGN,
+	token.OR:               LOGICALOR,
+	token.AND:              LOGICALAND,
+	token.EQ:               EQUALS,
+	token.NQ:               EQUALS,
+	token.LT:               LESSGREATER,
+	token.GT:               LESSGREATER,
+	token.LE:               LESSGREATER,
+	token.GE:               LESSGREATER,
+	token.PLUS:             SUM,
+	token.MINUS:            SUM,
+	token.DIVIDE:           PRODUCT,
+	token.TIMES:            PRODUCT,
+	token.MODULO:           PRODUCT,
+	token.POWER:            POWER,
+	token.BITOR:            BITWISEOR,
+	token.BITXOR:           BITWISEXOR,
+	token.BITAND:           BITWISEAND,
+	token.SHIFTLEFT:        SHIFT,
+	token.SHIFTRIGHT:       SHIFT,
+	token.LPARAN:           CALL,
+	token.LBRACKET:         INDEX,
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -66,10 +108,19 @@ func (p *Parser) curPrecedence() int {
 type Parser struct {
 	l      *lexer.Lexer
 	errors []string
+	// lexErrors is the number of lexer errors already copied into errors.
+	lexErrors int
 
 	curToken  token.Token
 	peekToken token.Token
 
+	// loopDepth is the number of loops enclosing the current token within
+	// the current function. break and continue are only valid inside a loop.
+	loopDepth int
+	// scopes records the names declared so far in each enclosing scope and
+	// whether they are constant, innermost last.
+	scopes []map[string]bool
+
 	prefixParseFns map[token.Type]prefixParseFn
 	infixParseFns  map[token.Type]infixParseFn
 }
@@ -79,24 +130,40 @@ func (p *Parser) Errors() []string {
 	return p.errors
 }
 
-func (p *Parser) peekError(t token.Type) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
+// errorAt records a parser error prefixed with the given source position.
+func (p *Parser) errorAt(pos token.Position, format string, a ...any) {
+	msg := fmt.Sprintf("%s: %s", pos, fmt.Sprintf(format, a...))
 	p.errors = append(p.errors, msg)
 }
 
+func (p *Parser) peekError(t token.Type) {
+	p.errorAt(
+		p.peekToken.Start,
+		"expected next token to be %s, got %s instead", t, p.peekToken.Type,
+	)
+}
+
 // New creates a new Parser.
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{l: l, errors: []string{}}
+	p.pushScope()
 
 	p.prefixParseFns = make(map[token.Type]prefixParseFn)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.BITNOT, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.LPARAN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.WHILE, p.parseWhileExpression)
+	p.registerPrefix(token.FOR, p.parseForExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
@@ -107,12 +174,35 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.TIMES, p.parseInfixExpression)
 	p.registerInfix(token.DIVIDE, p.parseInfixExpression)
+	p.registerInfix(token.MODULO, p.parseInfixExpression)
+	p.registerInfix(token.POWER, p.parseInfixExpression)
+	p.registerInfix(token.BITAND, p.parseInfixExpression)
+	p.registerInfix(token.BITOR, p.parseInfixExpression)
+	p.registerInfix(token.BITXOR, p.parseInfixExpression)
+	p.registerInfix(token.SHIFTLEFT, p.parseInfixExpression)
+	p.registerInfix(token.SHIFTRIGHT, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LE, p.parseInfixExpression)
+	p.registerInfix(token.GE, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
 	p.registerInfix(token.LPARAN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUSASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUSASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.TIMESASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.DIVIDEASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MODULOASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.POWERASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.BITANDASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.BITORASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.BITXORASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SHIFTLEFTASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SHIFTRIGHTASSIGN, p.parseAssignExpression)
 
 	// Read two tokens, so curToken and peekToken are both set.
 	p.nextToken()
@@ -120,6 +210,11 @@ func New(l *lexer.Lexer) *Parser {
 	return p
 }
 
+// parseIllegal skips an ILLEGAL token. The lexer has already reported why it is illegal.
+func (p *Parser) parseIllegal() ast.Expression {
+	return nil
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
@@ -128,9 +223,27 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		if b, ok := new(big.Int).SetString(p.curToken.Literal, 0); ok {
+			lit.Big = b
+			return lit
+		}
+	}
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.errorAt(p.curToken.Start, "could not parse %q as integer", p.curToken.Literal)
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		p.errorAt(p.curToken.Start, "could not parse %q as float", p.curToken.Literal)
 		return nil
 	}
 
@@ -181,6 +294,15 @@ func (p *Parser) parseIfExpression() ast.Expression {
 
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			elseIf, ok := p.parseIfExpression().(*ast.IfExpression)
+			if !ok {
+				return nil
+			}
+			exp.ElseIf = elseIf
+			return exp
+		}
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -200,6 +322,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		}
 		p.nextToken()
 	}
+	block.End = p.curToken.End
 	return block
 }
 
@@ -209,12 +332,26 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	p.pushScope()
+	defer p.popScope()
+	if !p.parseFunctionParameters(lit) {
+		return nil
+	}
+	for _, param := range lit.Parameters {
+		p.declarePattern(param, false)
+	}
+	if lit.Rest != nil {
+		p.declare(lit.Rest, false)
+	}
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	// A loop around the function literal does not enclose its body.
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
 	lit.Body = p.parseBlockStatement()
+	p.loopDepth = loopDepth
 	return lit
 }
 
@@ -225,12 +362,12 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.End = p.curToken.End
 	return array
 }
 
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken}
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
@@ -242,7 +379,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 
-		hash.Pairs[key] = value
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
 		}
@@ -251,33 +388,56 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	if !p.expectPeek(token.RBRACE) {
 		return nil
 	}
+	hash.End = p.curToken.End
 	return hash
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// parseFunctionParameters parses the parameter list of lit: plain
+// parameters, parameters with a default value and a final ...rest parameter.
+// It reports whether the list is well formed.
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) bool {
+	lit.Parameters = []ast.Pattern{}
+	lit.Defaults = []ast.Expression{}
 
 	if p.peekTokenIs(token.RPARAN) {
 		p.nextToken()
-		return identifiers
+		return true
 	}
 
-	p.nextToken()
-
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
+	for {
+		if p.peekTokenIs(token.ELLIPSIS) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
+			lit.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if p.peekTokenIs(token.COMMA) {
+				p.errorAt(p.peekToken.Start, "rest parameter must be last")
+				return false
+			}
+			break
+		}
 
-	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
+		param := p.parsePatternElement()
+		if param.Target == nil {
+			return false
+		}
+		if n := len(lit.Defaults); param.Default == nil && n > 0 && lit.Defaults[n-1] != nil {
+			p.errorAt(param.Target.Span().Start,
+				"parameter %s without default follows parameter with default", param.Target)
+			return false
+		}
+		lit.Parameters = append(lit.Parameters, param.Target)
+		lit.Defaults = append(lit.Defaults, param.Default)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
 		p.nextToken()
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, ident)
 	}
 
-	if !p.expectPeek(token.RPARAN) {
-		return nil
-	}
-	return identifiers
+	return p.expectPeek(token.RPARAN)
 }
 
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
@@ -287,6 +447,10 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 		Left:     left,
 	}
 	precedence := p.curPrecedence()
+	if p.curTokenIs(token.POWER) {
+		// ** is right associative
+		precedence--
+	}
 	p.nextToken()
 	exp.Right = p.parseExpression(precedence)
 	return exp
@@ -295,22 +459,125 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseExpressionList(token.RPARAN)
+	exp.End = p.curToken.End
 	return exp
 }
 
+// parseIndexExpression parses either an index expression a[i] or a slice
+// expression a[low:high], where both bounds are optional.
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	tok := p.curToken
 	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+
+	var index ast.Expression
+	if !p.curTokenIs(token.COLON) {
+		index = p.parseExpression(LOWEST)
+		if !p.peekTokenIs(token.COLON) {
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: tok, Left: left, Index: index, End: p.curToken.End}
+		}
+		p.nextToken()
+	}
+
+	exp := &ast.SliceExpression{Token: tok, Left: left, Low: index}
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.High = p.parseExpression(LOWEST)
+	}
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
+	exp.End = p.curToken.End
+	return exp
+}
+
+// parseAssignExpression parses an assignment to an identifier or an index
+// expression. Assignment is right-associative, so a = b = 1 assigns 1 to both.
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	exp := &ast.AssignExpression{Token: p.curToken, Target: target, Operator: p.curToken.Literal}
+	switch target := target.(type) {
+	case *ast.Identifier:
+		if p.isConst(target.Value) {
+			p.errorAt(p.curToken.Start, "cannot assign to constant %s", target)
+			return nil
+		}
+	case *ast.IndexExpression:
+	case nil:
+		return nil
+	default:
+		p.errorAt(p.curToken.Start, "cannot assign to %s", target)
+		return nil
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(ASSIGN - 1)
 	return exp
 }
 
+func (p *Parser) pushScope() {
+	p.scopes = append(p.scopes, map[string]bool{})
+}
+
+func (p *Parser) popScope() {
+	p.scopes = p.scopes[:len(p.scopes)-1]
+}
+
+// declare records a binding of ident in the innermost scope. Redeclaring a
+// constant of the same scope is an error.
+func (p *Parser) declare(ident *ast.Identifier, constant bool) {
+	scope := p.scopes[len(p.scopes)-1]
+	if scope[ident.Value] {
+		p.errorAt(ident.Token.Start, "cannot redeclare constant %s", ident)
+	}
+	scope[ident.Value] = constant
+}
+
+// declarePattern declares every name bound by pattern.
+func (p *Parser) declarePattern(pattern ast.Pattern, constant bool) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" || constant {
+			p.declare(pattern, constant)
+		}
+	case *ast.ArrayPattern:
+		for _, el := range pattern.Elements {
+			p.declarePattern(el.Target, constant)
+		}
+		if pattern.Rest != nil {
+			p.declare(pattern.Rest, constant)
+		}
+	case *ast.HashPattern:
+		for _, entry := range pattern.Entries {
+			p.declarePattern(entry.Target, constant)
+		}
+		if pattern.Rest != nil {
+			p.declare(pattern.Rest, constant)
+		}
+	}
+}
+
+// isConst reports whether the nearest declaration of name seen so far is a
+// constant. Names declared elsewhere, such as by the host, are checked when
+// the program runs.
+func (p *Parser) isConst(name string) bool {
+	for i := len(p.scopes) - 1; i >= 0; i-- {
+		if constant, ok := p.scopes[i][name]; ok {
+			return constant
+		}
+	}
+	return false
+}
+
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	if errs := p.l.Errors(); len(errs) > p.lexErrors {
+		p.errors = append(p.errors, errs[p.lexErrors:]...)
+		p.lexErrors = len(errs)
+	}
 }
 
 // ParseProgram parses a Monkey program.
@@ -324,15 +591,20 @@ func (p *Parser) ParseProgram() *ast.Program {
 		}
 		p.nextToken()
 	}
+	prg.Comments = p.l.Comments()
 	return prg
 }
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.BREAK, token.CONTINUE:
+		return p.parseLoopControlStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -340,25 +612,363 @@ func (p *Parser) parseStatement() ast.Statement {
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.curToken}
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		if stmt.Pattern = p.parsePattern(); stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		}
+	}
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil {
+		fn.Name = stmt.Name.Value
+	}
+
+	constant := stmt.Token.Type == token.CONST
+	if stmt.Name != nil {
+		p.declare(stmt.Name, constant)
+	} else {
+		p.declarePattern(stmt.Pattern, constant)
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parsePattern parses the binding target starting at the current token: an
+// identifier, an array pattern or a hash pattern.
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	case token.LBRACKET:
+		return p.parseArrayPattern(p.parsePatternElement)
+	case token.LBRACE:
+		return p.parseHashPattern(p.parsePatternElement)
+	default:
+		p.errorAt(p.curToken.Start, "expected a name or pattern, got %s instead", p.curToken.Type)
+		return nil
+	}
+}
+
+// parsePatternElement parses a pattern followed by an optional = default.
+// Target is nil if the pattern is malformed.
+func (p *Parser) parsePatternElement() ast.PatternElement {
+	el := ast.PatternElement{Target: p.parsePattern()}
+	if el.Target != nil && p.peekTokenIs(token.ASSIGN) {
+		p.nextToken()
+		p.nextToken()
+		el.Default = p.parseExpression(LOWEST)
+	}
+	return el
+}
+
+// parseRestPattern parses ...name, which must be the last item before end.
+func (p *Parser) parseRestPattern(end token.Type) *ast.Identifier {
 	if !p.expectPeek(token.IDENT) {
 		return nil
 	}
-	stmt.Name = &ast.Identifier{
-		Token: p.curToken,
-		Value: p.curToken.Literal,
+	rest := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.COMMA) {
+		p.errorAt(p.peekToken.Start, "rest element must be last")
+		return nil
 	}
-	if !p.expectPeek(token.ASSIGN) {
+	if !p.expectPeek(end) {
+		return nil
+	}
+	return rest
+}
+
+// parseArrayPattern parses [elem, ..., ...rest], reading each element with parseElement.
+func (p *Parser) parseArrayPattern(parseElement func() ast.PatternElement) ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if pattern.Rest = p.parseRestPattern(token.RBRACKET); pattern.Rest == nil {
+				return nil
+			}
+			pattern.End = p.curToken.End
+			return pattern
+		}
+
+		el := parseElement()
+		if el.Target == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, el)
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+	pattern.End = p.curToken.End
+	return pattern
+}
+
+// parseHashPattern parses {key: elem, name, ..., ...rest}, reading each
+// element with parseElement.
+func (p *Parser) parseHashPattern(parseElement func() ast.PatternElement) ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if pattern.Rest = p.parseRestPattern(token.RBRACE); pattern.Rest == nil {
+				return nil
+			}
+			pattern.End = p.curToken.End
+			return pattern
+		}
+
+		var entry ast.HashPatternEntry
+		switch {
+		case p.curTokenIs(token.IDENT) && !p.peekTokenIs(token.COLON):
+			entry.Key = p.curToken.Literal
+			entry.PatternElement = parseElement()
+		case p.curTokenIs(token.IDENT) || p.curTokenIs(token.STRING):
+			entry.Key = p.curToken.Literal
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			entry.PatternElement = parseElement()
+		default:
+			p.errorAt(p.curToken.Start, "expected a hash pattern key, got %s instead", p.curToken.Type)
+			return nil
+		}
+		if entry.Target == nil {
+			return nil
+		}
+		pattern.Entries = append(pattern.Entries, entry)
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+	pattern.End = p.curToken.End
+	return pattern
+}
+
+// parseMatchExpression parses match (subject) { pattern [when guard] => body, ... }.
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPARAN) {
 		return nil
 	}
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPARAN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		arm, ok := p.parseMatchArm()
+		if !ok {
+			return nil
+		}
+		exp.Arms = append(exp.Arms, arm)
 
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
 	p.nextToken()
+	exp.End = p.curToken.End
+	return exp
+}
+
+// parseMatchArm parses pattern [when guard] => body. The names bound by the
+// pattern are scoped to the arm.
+func (p *Parser) parseMatchArm() (ast.MatchArm, bool) {
+	arm := ast.MatchArm{Pattern: p.parseMatchPattern()}
+	if arm.Pattern == nil {
+		return arm, false
+	}
+	p.pushScope()
+	defer p.popScope()
+	p.declarePattern(arm.Pattern, false)
+
+	if p.peekTokenIs(token.WHEN) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+	if !p.expectPeek(token.ARROW) {
+		return arm, false
+	}
+	p.nextToken()
+	arm.Body = p.parseExpression(LOWEST)
+	return arm, true
+}
+
+// parseMatchPattern parses the pattern of a match arm. Besides the binding
+// patterns it accepts literals; the identifier _ matches anything.
+func (p *Parser) parseMatchPattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE:
+		if lit := p.prefixParseFns[p.curToken.Type](); lit != nil {
+			return &ast.LiteralPattern{Value: lit}
+		}
+		return nil
+	case token.MINUS:
+		if p.peekTokenIs(token.INT) || p.peekTokenIs(token.FLOAT) {
+			return &ast.LiteralPattern{Value: p.parsePrefixExpression()}
+		}
+	case token.LBRACKET:
+		return p.parseArrayPattern(p.parseMatchPatternElement)
+	case token.LBRACE:
+		return p.parseHashPattern(p.parseMatchPatternElement)
+	}
+	p.errorAt(p.curToken.Start, "expected a match pattern, got %s instead", p.curToken.Type)
+	return nil
+}
+
+func (p *Parser) parseMatchPatternElement() ast.PatternElement {
+	return ast.PatternElement{Target: p.parseMatchPattern()}
+}
+
+func (p *Parser) parseWhileExpression() ast.Expression {
+	exp := &ast.WhileExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPARAN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPARAN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.pushScope()
+	defer p.popScope()
+	exp.Body = p.parseLoopBody()
+	return exp
+}
+
+func (p *Parser) parseForExpression() ast.Expression {
+	exp := &ast.ForExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPARAN) {
+		return nil
+	}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Variable = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPARAN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.pushScope()
+	defer p.popScope()
+	p.declare(exp.Variable, false)
+	exp.Body = p.parseLoopBody()
+	return exp
+}
+
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+	return p.parseBlockStatement()
+}
+
+// parseLoopControlStatement parses break and continue.
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	tok := p.curToken
+	if p.loopDepth == 0 {
+		p.errorAt(tok.Start, "%s outside loop", tok.Literal)
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}
+	}
+	return &ast.ContinueStatement{Token: tok}
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+
 	stmt.Value = p.parseExpression(LOWEST)
-	for !p.curTokenIs(token.SEMICOLON) {
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 	return stmt
 }
 
+// parseTryExpression parses try { } followed by catch (e) { }, finally { }
+// or both.
+func (p *Parser) parseTryExpression() ast.Expression {
+	exp := &ast.TryExpression{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	exp.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+		if !p.expectPeek(token.LPARAN) || !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		exp.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if !p.expectPeek(token.RPARAN) || !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		p.pushScope()
+		p.declare(exp.CatchParam, false)
+		exp.Catch = p.parseBlockStatement()
+		p.popScope()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		exp.Finally = p.parseBlockStatement()
+	}
+
+	if exp.Catch == nil && exp.Finally == nil {
+		p.errorAt(exp.Token.Start, "try without catch or finally")
+		return nil
+	}
+	return exp
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
@@ -366,7 +976,7 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 	return stmt
@@ -429,8 +1039,19 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 }
 
 func (p *Parser) noPrefixParseFnError(t token.Type) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.errorAt(p.curToken.Start, "no prefix parse function for %s found", t)
+}
+
+// parseListElement parses an element of an argument or array list, which may
+// be spread with ...
+func (p *Parser) parseListElement() ast.Expression {
+	if !p.curTokenIs(token.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+	spread := &ast.SpreadExpression{Token: p.curToken}
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+	return spread
 }
 
 func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
@@ -440,11 +1061,11 @@ func (p *Parser) parseExpressionList(end token.Type) []ast.Expression {
 		return list
 	}
 	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
+	list = append(list, p.parseListElement())
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseListElement())
 	}
 	if !p.expectPeek(end) {
 		return nil
@@ -6,6 +6,7 @@ import (
 
 	"github.com/w40141/monkey-language/golang/ast"
 	"github.com/w40141/monkey-language/golang/lexer"
+	"github.com/w40141/monkey-language/golang/token"
 )
 
 func TestParsingHashLiteral(t *testing.T) {
@@ -80,13 +81,13 @@ func TestParsingHashLiteral(t *testing.T) {
 			t.Fatalf("number: %d, len(hash.Pairs) not 3. got=%d", i, length)
 		}
 
-		for key, value := range hash.Pairs {
-			literal, ok := key.(*ast.StringLiteral)
+		for _, pair := range hash.Pairs {
+			literal, ok := pair.Key.(*ast.StringLiteral)
 			if !ok {
-				t.Errorf("key is not ast.StringLiteral. got=%T", key)
+				t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
 			}
 			testFunc := tt.want.expected[literal.String()]
-			testFunc(value)
+			testFunc(pair.Value)
 		}
 	}
 }
@@ -113,6 +114,81 @@ func TestParsingIndexExpression(t *testing.T) {
 	}
 }
 
+func TestParsingSliceExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		low   any
+		high  any
+	}{
+		{"a[1:3]", int64(1), int64(3)},
+		{"a[:2]", nil, int64(2)},
+		{"a[2:]", int64(2), nil},
+		{"a[:]", nil, nil},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+
+		stmt, ok := prg.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", prg.Statements[0])
+		}
+		slice, ok := stmt.Expression.(*ast.SliceExpression)
+		if !ok {
+			t.Fatalf("exp not *ast.SliceExpression. got=%T", stmt.Expression)
+		}
+		if !testIdentifire(t, slice.Left, "a") {
+			return
+		}
+		for _, bound := range []struct {
+			got  ast.Expression
+			want any
+		}{{slice.Low, tt.low}, {slice.High, tt.high}} {
+			if bound.want == nil {
+				if bound.got != nil {
+					t.Errorf("bound is not nil. got=%s", bound.got)
+				}
+				continue
+			}
+			testLiteralExpressin(t, bound.got, bound.want)
+		}
+		if slice.String() != "(a"+tt.input[1:]+")" {
+			t.Errorf("slice.String() wrong. got=%q", slice.String())
+		}
+	}
+}
+
+func TestLoopExpressions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"while (x < 10) { x += 1 }", "while(x < 10) (x += 1)"},
+		{"for (x in [1, 2]) { puts(x); }", "for(x in [1, 2]) puts(x)"},
+		{"while (true) { if (x) { break; } continue; }", "whiletrue ifx break;continue;"},
+		{"for (k in h) { while (k) { break } }", "for(k in h) whilek break;"},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		if got := prg.String(); got != tt.want {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.want, got)
+		}
+	}
+
+	prg := parseProgram(t, "for (item in items) { break }")
+	stmt := prg.Statements[0].(*ast.ExpressionStatement)
+	loop, ok := stmt.Expression.(*ast.ForExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.ForExpression. got=%T", stmt.Expression)
+	}
+	testIdentifire(t, loop.Variable, "item")
+	testIdentifire(t, loop.Iterable, "items")
+	if _, ok := loop.Body.Statements[0].(*ast.BreakStatement); !ok {
+		t.Errorf("body statement not *ast.BreakStatement. got=%T", loop.Body.Statements[0])
+	}
+}
+
 func TestParsingArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 	prg := parseProgram(t, input)
@@ -212,6 +288,217 @@ func TestFunctionParmeterParsing(t *testing.T) {
 	}
 }
 
+func TestFunctionDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		rest  string
+	}{
+		{"fn(x, y = 10) {}", "fn(x, y = 10) ", ""},
+		{"fn(x = 1, y = x + 1) {}", "fn(x = 1, y = (x + 1)) ", ""},
+		{"fn(first, ...rest) {}", "fn(first, ...rest) ", "rest"},
+		{"fn(...args) {}", "fn(...args) ", "args"},
+		{"fn(a = 1, ...r) {}", "fn(a = 1, ...r) ", "r"},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		stmt := prg.Statements[0].(*ast.ExpressionStatement)
+		function, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+		}
+		if got := function.String(); got != tt.want {
+			t.Errorf("function.String() wrong. expected=%q, got=%q", tt.want, got)
+		}
+		if len(function.Defaults) != len(function.Parameters) {
+			t.Errorf("len(Defaults)=%d, len(Parameters)=%d", len(function.Defaults), len(function.Parameters))
+		}
+		if tt.rest == "" {
+			if function.Rest != nil {
+				t.Errorf("unexpected rest parameter %s", function.Rest)
+			}
+			continue
+		}
+		testIdentifire(t, function.Rest, tt.rest)
+	}
+}
+
+func TestDestructuringPatterns(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"let [a, b] = x;", "let [a, b] = x;"},
+		{"let [] = x;", "let [] = x;"},
+		{"let [a, b = 2, ...rest] = x;", "let [a, b = 2, ...rest] = x;"},
+		{"let [[a, b], {c}] = x;", "let [[a, b], {c}] = x;"},
+		{"let {name, age: years} = p;", "let {name, age: years} = p;"},
+		{`let {"first name": first, size = 1, ...others} = p;`, "let {first name: first, size = 1, ...others} = p;"},
+		{"let {pos: {x, y = 0}, tags: [t]} = p;", "let {pos: {x, y = 0}, tags: [t]} = p;"},
+		{"fn([a, b], {c} = {}) { a }", "fn([a, b], {c} = {}) a"},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		if got := prg.String(); got != tt.want {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.want, got)
+		}
+	}
+
+	prg := parseProgram(t, "let {a, b: [c, d = 1]} = h;")
+	stmt := prg.Statements[0].(*ast.LetStatement)
+	if stmt.Name != nil {
+		t.Errorf("stmt.Name not nil. got=%s", stmt.Name)
+	}
+	pattern, ok := stmt.Pattern.(*ast.HashPattern)
+	if !ok {
+		t.Fatalf("stmt.Pattern not *ast.HashPattern. got=%T", stmt.Pattern)
+	}
+	if len(pattern.Entries) != 2 || pattern.Entries[0].Key != "a" || pattern.Entries[1].Key != "b" {
+		t.Fatalf("wrong entries. got=%+v", pattern.Entries)
+	}
+	inner, ok := pattern.Entries[1].Target.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("entry target not *ast.ArrayPattern. got=%T", pattern.Entries[1].Target)
+	}
+	testIdentifire(t, inner.Elements[0].Target.(*ast.Identifier), "c")
+	testIntegerLiteral(t, inner.Elements[1].Default, 1)
+	if span := pattern.Span(); span.End.Offset != 22 {
+		t.Errorf("pattern span end wrong. got=%d", span.End.Offset)
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"match (x) { 1 => a, _ => b }", "matchx {1 => a, _ => b}"},
+		{"match (x) { -1 => a, 2.5 => b, \"s\" => c, true => d, }", "matchx {(-1) => a, 2.5 => b, s => c, true => d}"},
+		{"match (p) { [a, 0] when a > 1 => a * 2, [_, ...r] => r }", "matchp {[a, 0] when (a > 1) => (a * 2), [_, ...r] => r}"},
+		{"match (p) { {kind: \"circle\", r} => r, {} => 0 }", "matchp {{kind: circle, r} => r, {} => 0}"},
+		{"match (x) {}", "matchx {}"},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		if got := prg.String(); got != tt.want {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.want, got)
+		}
+	}
+
+	prg := parseProgram(t, "match (v) { [1, n] when n => n }")
+	stmt := prg.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.MatchExpression. got=%T", stmt.Expression)
+	}
+	testIdentifire(t, exp.Subject, "v")
+	if len(exp.Arms) != 1 {
+		t.Fatalf("wrong number of arms. got=%d", len(exp.Arms))
+	}
+	arm := exp.Arms[0]
+	pattern, ok := arm.Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("pattern not *ast.ArrayPattern. got=%T", arm.Pattern)
+	}
+	lit, ok := pattern.Elements[0].Target.(*ast.LiteralPattern)
+	if !ok {
+		t.Fatalf("element not *ast.LiteralPattern. got=%T", pattern.Elements[0].Target)
+	}
+	testIntegerLiteral(t, lit.Value, 1)
+	testIdentifire(t, arm.Guard, "n")
+	testIdentifire(t, arm.Body, "n")
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`try { f() } catch (e) { g(e) }`, "try f()catch(e) g(e)"},
+		{`try { f() } finally { g() }`, "try f()finally g()"},
+		{`try { f() } catch (e) { 1 } finally { 2 }`, "try f()catch(e) 1finally 2"},
+		{`throw "boom";`, "throw boom;"},
+		{`throw {"message": m}`, "throw {message:m};"},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		if got := prg.String(); got != tt.want {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.want, got)
+		}
+	}
+
+	prg := parseProgram(t, "try { a } catch (err) { b }")
+	stmt := prg.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.TryExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.TryExpression. got=%T", stmt.Expression)
+	}
+	testIdentifire(t, exp.CatchParam, "err")
+	if exp.Finally != nil {
+		t.Errorf("exp.Finally not nil. got=%s", exp.Finally)
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`const x = 1;`, "const x = 1;"},
+		{`const [a, b] = xs;`, "const [a, b] = xs;"},
+		{`const x = 1; let f = fn() { let x = 2; x = 3 };`, "const x = 1;let f = fn() let x = 2;(x = 3);"},
+		{`const x = 1; let f = fn(x) { x += 1 };`, "const x = 1;let f = fn(x) (x += 1);"},
+		{`const x = 1; for (x in xs) { x = 2 }`, "const x = 1;for(x in xs) (x = 2)"},
+		{`const e = 1; try { f() } catch (e) { e = 2 }`, "const e = 1;try f()catch(e) (e = 2)"},
+		{`let x = 1; x = 2; const y = x;`, "let x = 1;(x = 2)const y = x;"},
+		{`const x = 1; let i = 0; while (i < 1) { let x = 2; i += 1 }`, "const x = 1;let i = 0;while(i < 1) let x = 2;(i += 1)"},
+		{`while (c) { const y = 2 }; let y = 3`, "whilec const y = 2;let y = 3;"},
+	}
+
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		if got := prg.String(); got != tt.want {
+			t.Errorf("program.String() wrong. expected=%q, got=%q", tt.want, got)
+		}
+	}
+}
+
+func TestFunctionName(t *testing.T) {
+	prg := parseProgram(t, "let add = fn(a, b) { a + b }; let f = add; fn() {}")
+	fn := prg.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if fn.Name != "add" {
+		t.Errorf("fn.Name wrong. expected=%q, got=%q", "add", fn.Name)
+	}
+	anon := prg.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if anon.Name != "" {
+		t.Errorf("anonymous fn.Name not empty. got=%q", anon.Name)
+	}
+}
+
+func TestSpreadArguments(t *testing.T) {
+	prg := parseProgram(t, "f(1, ...xs, ...[2, 3])")
+	stmt := prg.Statements[0].(*ast.ExpressionStatement)
+	call, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T", stmt.Expression)
+	}
+	if len(call.Arguments) != 3 {
+		t.Fatalf("wrong number of arguments. got=%d", len(call.Arguments))
+	}
+	spread, ok := call.Arguments[1].(*ast.SpreadExpression)
+	if !ok {
+		t.Fatalf("argument is not ast.SpreadExpression. got=%T", call.Arguments[1])
+	}
+	testIdentifire(t, spread.Value, "xs")
+	if got := call.String(); got != "f(1, ...xs, ...[2, 3])" {
+		t.Errorf("call.String() wrong. got=%q", got)
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := `fn(x, y) { x + y; }`
 	prg := parseProgram(t, input)
@@ -304,6 +591,47 @@ func TestIfElseExpression(t *testing.T) {
 	}
 }
 
+func TestElseIfExpression(t *testing.T) {
+	input := "if (a) { 1 } else if (b) { 2 } else if (c) { 3 } else { 4 }"
+	prg := parseProgram(t, input)
+
+	stmt, ok := prg.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			prg.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	for _, cond := range []string{"a", "b", "c"} {
+		if exp == nil {
+			t.Fatalf("else if chain ended before %s", cond)
+		}
+		testIdentifire(t, exp.Condition, cond)
+		if exp.ElseIf != nil && exp.ALternative != nil {
+			t.Fatalf("both ElseIf and ALternative set for %s", cond)
+		}
+		if exp.ElseIf == nil {
+			break
+		}
+		exp = exp.ElseIf
+	}
+	if exp.ALternative == nil {
+		t.Fatal("final else missing")
+	}
+
+	want := "ifa 1else ifb 2else ifc 3else 4"
+	if got := prg.String(); got != want {
+		t.Errorf("program.String() wrong. expected=%q, got=%q", want, got)
+	}
+	if span := stmt.Span(); span.End.Offset != len(input) {
+		t.Errorf("span end wrong. expected=%d, got=%d", len(input), span.End.Offset)
+	}
+}
+
 func TestIfExpression(t *testing.T) {
 	input := "if (x < y) { x }"
 	prg := parseProgram(t, input)
@@ -394,6 +722,22 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"!-a;",
 			"(!(-a))",
 		},
+		{
+			"x = y = 1 + 2",
+			"(x = (y = (1 + 2)))",
+		},
+		{
+			"a[i] += b || c",
+			"((a[i]) += (b || c))",
+		},
+		{
+			"x **= 2 ** 3",
+			"(x **= (2 ** 3))",
+		},
+		{
+			`{"z": 1, "a": b + c, 3: 4}`,
+			`{z:1, a:(b + c), 3:4}`,
+		},
 		{
 			"a + b + c;",
 			"((a + b) + c)",
@@ -490,6 +834,54 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a <= b == c >= d",
+			"((a <= b) == (c >= d))",
+		},
+		{
+			"a + b % c",
+			"(a + (b % c))",
+		},
+		{
+			"-a ** b",
+			"(-(a ** b))",
+		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c))",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)))",
+		},
+		{
+			"a & b == c",
+			"((a & b) == c)",
+		},
+		{
+			"1 << a + b",
+			"(1 << (a + b))",
+		},
+		{
+			"~a & b >> 2",
+			"((~a) & (b >> 2))",
+		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a && b || c && d",
+			"((a && b) || (c && d))",
+		},
+		{
+			"a < b && b == c || !d",
+			"(((a < b) && (b == c)) || (!d))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -584,6 +976,42 @@ func TestParsingInfixExpressions(t *testing.T) {
 				right:    5,
 			},
 		},
+		{
+			input: "1 <= 5;",
+			want: want{
+				length:   1,
+				operator: "<=",
+				left:     1,
+				right:    5,
+			},
+		},
+		{
+			input: "1 >= 5;",
+			want: want{
+				length:   1,
+				operator: ">=",
+				left:     1,
+				right:    5,
+			},
+		},
+		{
+			input: "true && false;",
+			want: want{
+				length:   1,
+				operator: "&&",
+				left:     true,
+				right:    false,
+			},
+		},
+		{
+			input: "true || false;",
+			want: want{
+				length:   1,
+				operator: "||",
+				left:     true,
+				right:    false,
+			},
+		},
 		{
 			input: "true == true;",
 			want: want{
@@ -718,6 +1146,38 @@ func TestIntegerLiteralExpression(t *testing.T) {
 				literal: "5",
 			},
 		},
+		{
+			input: "0xFF;",
+			want: want{
+				length:  1,
+				value:   255,
+				literal: "0xFF",
+			},
+		},
+		{
+			input: "0o755;",
+			want: want{
+				length:  1,
+				value:   493,
+				literal: "0o755",
+			},
+		},
+		{
+			input: "0b1010;",
+			want: want{
+				length:  1,
+				value:   10,
+				literal: "0b1010",
+			},
+		},
+		{
+			input: "1_000_000;",
+			want: want{
+				length:  1,
+				value:   1000000,
+				literal: "1_000_000",
+			},
+		},
 	}
 	for _, tt := range tests {
 		prg := parseProgram(t, tt.input)
@@ -743,6 +1203,52 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestBigIntegerLiteralExpression(t *testing.T) {
+	input := "99999999999999999999;"
+	prg := parseProgram(t, input)
+	stmt := prg.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.IntegerLiteral. got=%T", stmt.Expression)
+	}
+	if literal.Big == nil || literal.Big.String() != "99999999999999999999" {
+		t.Errorf("literal.Big not 99999999999999999999. got=%v", literal.Big)
+	}
+	if literal.String() != "99999999999999999999" {
+		t.Errorf("literal.String() not 99999999999999999999. got=%s", literal.String())
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{input: "3.14;", want: 3.14},
+		{input: "1e3;", want: 1000},
+		{input: "2.5E-2;", want: 0.025},
+	}
+	for _, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		if l := len(prg.Statements); l != 1 {
+			t.Fatalf("len(prg.Statements) is not 1. got=%d", l)
+		}
+
+		stmt, ok := prg.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("s not *ast.ExpressionStatement. got=%T", prg.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.FloatLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != tt.want {
+			t.Errorf("literal.Value not %g. got=%g", tt.want, literal.Value)
+		}
+	}
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	type want struct {
 		length int
@@ -808,6 +1314,16 @@ func TestLetStatements(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Let statement without semicolon",
+			input: "let x = 5",
+			want: want{
+				length: 1,
+				identifiers: []identifier{
+					{"x", 5},
+				},
+			},
+		},
 		{
 			name: "Multi let statements",
 			input: `
@@ -1043,3 +1559,197 @@ func testBoolean(t *testing.T, exp ast.Expression, value bool) bool {
 
 	return true
 }
+
+func TestProgramComments(t *testing.T) {
+	input := `
+	// add two numbers
+	let add = fn(x, y) { x /* left */ + y };
+	add(1, 2) / 3`
+	prg := parseProgram(t, input)
+
+	if l := len(prg.Statements); l != 2 {
+		t.Fatalf("len(prg.Statements) is not 2. got=%d", l)
+	}
+	want := "let add = fn(x, y) (x + y);(add(1, 2) / 3)"
+	if prg.String() != want {
+		t.Errorf("prg.String() is not %q. got=%q", want, prg.String())
+	}
+
+	comments := []string{"// add two numbers", "/* left */"}
+	if len(prg.Comments) != len(comments) {
+		t.Fatalf("len(prg.Comments) is not %d. got=%d", len(comments), len(prg.Comments))
+	}
+	for i, c := range comments {
+		if prg.Comments[i].Literal != c {
+			t.Errorf("prg.Comments[%d] is not %q. got=%q", i, c, prg.Comments[i].Literal)
+		}
+	}
+}
+
+func TestParserErrorPosition(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "let x 5;",
+			want:  "1:7: expected next token to be =, got INT instead",
+		},
+		{
+			input: "let a = 1;\nadd(1, 2;",
+			want:  "2:9: expected next token to be ), got ; instead",
+		},
+		{
+			input: `let s = "abc;`,
+			want:  "1:9: unterminated string literal",
+		},
+		{
+			input: `puts("a\zb");`,
+			want:  "1:8: unknown escape sequence \\z",
+		},
+		{
+			input: "1 + x = 2;",
+			want:  "1:7: cannot assign to (1 + x)",
+		},
+		{
+			input: "a[1:] = [];",
+			want:  "1:7: cannot assign to (a[1:])",
+		},
+		{
+			input: "if (true) { break; }",
+			want:  "1:13: break outside loop",
+		},
+		{
+			input: "while (true) { fn() { continue } }",
+			want:  "1:23: continue outside loop",
+		},
+		{
+			input: "for (1 in a) {}",
+			want:  "1:6: expected next token to be IDENT, got INT instead",
+		},
+		{
+			input: "fn(...a, b) {}",
+			want:  "1:8: rest parameter must be last",
+		},
+		{
+			input: "fn(a = 1, b) {}",
+			want:  "1:11: parameter b without default follows parameter with default",
+		},
+		{
+			input: "fn(1) {}",
+			want:  "1:4: expected a name or pattern, got INT instead",
+		},
+		{
+			input: "let [a, ...r, b] = x;",
+			want:  "1:13: rest element must be last",
+		},
+		{
+			input: `let {"a"} = x;`,
+			want:  "1:9: expected next token to be :, got } instead",
+		},
+		{
+			input: "let {1: a} = x;",
+			want:  "1:6: expected a hash pattern key, got INT instead",
+		},
+		{
+			input: "let [a b] = x;",
+			want:  "1:8: expected next token to be ,, got IDENT instead",
+		},
+		{
+			input: "match (x) { 1 + 2 => 3 }",
+			want:  "1:15: expected next token to be =>, got + instead",
+		},
+		{
+			input: "match (x) { (y) => 3 }",
+			want:  "1:13: expected a match pattern, got ( instead",
+		},
+		{
+			input: "match (x) { 1 => 2 3 => 4 }",
+			want:  "1:20: expected next token to be ,, got INT instead",
+		},
+		{
+			input: "try { 1 }",
+			want:  "1:1: try without catch or finally",
+		},
+		{
+			input: "try { 1 } catch { 2 }",
+			want:  "1:17: expected next token to be (, got { instead",
+		},
+		{
+			input: "const x = 1; let x = 2;",
+			want:  "1:18: cannot redeclare constant x",
+		},
+		{
+			input: "let x = 1; const [a, x] = y; const x = 2;",
+			want:  "1:36: cannot redeclare constant x",
+		},
+		{
+			input: "const x = 1; while (c) { x = 2 }",
+			want:  "1:28: cannot assign to constant x",
+		},
+		{
+			input: "const x = 1;\nx = 2;",
+			want:  "2:3: cannot assign to constant x",
+		},
+		{
+			input: "const {a: [b]} = c; let f = fn() { b += 1 };",
+			want:  "1:38: cannot assign to constant b",
+		},
+		{
+			input: "let mask = 0x;",
+			want:  "1:12: hexadecimal literal has no digits",
+		},
+		{
+			input: "\n\n   }",
+			want:  "3:4: no prefix parse function for } found",
+		},
+	}
+
+	for i, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Fatalf("tests[%d] - parser has no errors", i)
+		}
+		if errors[0] != tt.want {
+			t.Errorf("tests[%d] - error wrong. expected=%q, got=%q", i, tt.want, errors[0])
+		}
+	}
+}
+
+func TestNodeSpan(t *testing.T) {
+	tests := []struct {
+		input string
+		start token.Position
+		end   token.Position
+	}{
+		{
+			input: "1 + 2 * 3",
+			start: token.Position{Offset: 0, Line: 1, Column: 1},
+			end:   token.Position{Offset: 9, Line: 1, Column: 10},
+		},
+		{
+			input: "  add(1,\n 2)",
+			start: token.Position{Offset: 2, Line: 1, Column: 3},
+			end:   token.Position{Offset: 12, Line: 2, Column: 4},
+		},
+		{
+			input: "if (x) { 1 } else { [1, 2][0] }",
+			start: token.Position{Offset: 0, Line: 1, Column: 1},
+			end:   token.Position{Offset: 31, Line: 1, Column: 32},
+		},
+	}
+
+	for i, tt := range tests {
+		prg := parseProgram(t, tt.input)
+		stmt := prg.Statements[0].(*ast.ExpressionStatement)
+		span := stmt.Expression.Span()
+		if span.Start != tt.start {
+			t.Errorf("tests[%d] - start wrong. expected=%+v, got=%+v", i, tt.start, span.Start)
+		}
+		if span.End != tt.end {
+			t.Errorf("tests[%d] - end wrong. expected=%+v, got=%+v", i, tt.end, span.End)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 // Package token defines constants representing the lexical tokens.
 package token
 
+import "fmt"
+
 const (
 	// ILLEGAL represent Special tokens
 	ILLEGAL = "ILLEGAL"
@@ -15,6 +17,9 @@ const (
 	FLOAT = "FLOAT"
 	// STRING represents strings.
 	STRING = "STRING"
+	// COMMENT represents line and block comments. Comments are not emitted by
+	// the lexer but kept as trivia.
+	COMMENT = "COMMENT"
 
 	// ASSIGN represents assignment operator.
 	ASSIGN = "="
@@ -28,6 +33,45 @@ const (
 	DIVIDE = "/"
 	// BANG represents bang operator.
 	BANG = "!"
+	// MODULO represents modulo operator.
+	MODULO = "%"
+	// POWER represents exponentiation operator.
+	POWER = "**"
+	// BITAND represents bitwise and operator.
+	BITAND = "&"
+	// BITOR represents bitwise or operator.
+	BITOR = "|"
+	// BITXOR represents bitwise xor operator.
+	BITXOR = "^"
+	// BITNOT represents bitwise not operator.
+	BITNOT = "~"
+	// SHIFTLEFT represents left shift operator.
+	SHIFTLEFT = "<<"
+	// SHIFTRIGHT represents right shift operator.
+	SHIFTRIGHT = ">>"
+
+	// PLUSASSIGN represents addition assignment operator.
+	PLUSASSIGN = "+="
+	// MINUSASSIGN represents subtraction assignment operator.
+	MINUSASSIGN = "-="
+	// TIMESASSIGN represents multiplication assignment operator.
+	TIMESASSIGN = "*="
+	// DIVIDEASSIGN represents division assignment operator.
+	DIVIDEASSIGN = "/="
+	// MODULOASSIGN represents modulo assignment operator.
+	MODULOASSIGN = "%="
+	// POWERASSIGN represents exponentiation assignment operator.
+	POWERASSIGN = "**="
+	// BITANDASSIGN represents bitwise and assignment operator.
+	BITANDASSIGN = "&="
+	// BITORASSIGN represents bitwise or assignment operator.
+	BITORASSIGN = "|="
+	// BITXORASSIGN represents bitwise xor assignment operator.
+	BITXORASSIGN = "^="
+	// SHIFTLEFTASSIGN represents left shift assignment operator.
+	SHIFTLEFTASSIGN = "<<="
+	// SHIFTRIGHTASSIGN represents right shift assignment operator.
+	SHIFTRIGHTASSIGN = ">>="
 
 	// COMMA represents comma.
 	COMMA = ","
@@ -35,6 +79,10 @@ const (
 	SEMICOLON = ";"
 	// COLON represents colon.
 	COLON = ":"
+	// ELLIPSIS represents the rest and spread operator.
+	ELLIPSIS = "..."
+	// ARROW separates the pattern and the result of a match arm.
+	ARROW = "=>"
 
 	// LPARAN represents left parenthesis.
 	LPARAN = "("
@@ -52,11 +100,21 @@ const (
 	LT = "<"
 	// GT represents greater than.
 	GT = ">"
+	// LE represents less than or equal to.
+	LE = "<="
+	// GE represents greater than or equal to.
+	GE = ">="
+	// AND represents logical and.
+	AND = "&&"
+	// OR represents logical or.
+	OR = "||"
 
 	// FUNCTION represents function keyword.
 	FUNCTION = "FUNCTION"
 	// LET represents let keyword.
 	LET = "LET"
+	// CONST represents const keyword.
+	CONST = "CONST"
 	// TRUE represents true keyword.
 	TRUE = "TRUE"
 	// FALSE represents false keyword.
@@ -67,6 +125,28 @@ const (
 	ELSE = "ELSE"
 	// RETURN represents return keyword.
 	RETURN = "RETURN"
+	// WHILE represents while keyword.
+	WHILE = "WHILE"
+	// FOR represents for keyword.
+	FOR = "FOR"
+	// IN represents in keyword.
+	IN = "IN"
+	// BREAK represents break keyword.
+	BREAK = "BREAK"
+	// CONTINUE represents continue keyword.
+	CONTINUE = "CONTINUE"
+	// MATCH represents match keyword.
+	MATCH = "MATCH"
+	// WHEN represents when keyword.
+	WHEN = "WHEN"
+	// THROW represents throw keyword.
+	THROW = "THROW"
+	// TRY represents try keyword.
+	TRY = "TRY"
+	// CATCH represents catch keyword.
+	CATCH = "CATCH"
+	// FINALLY represents finally keyword.
+	FINALLY = "FINALLY"
 
 	// EQ represents equal operator.
 	EQ = "=="
@@ -81,21 +161,76 @@ type Type string
 type Token struct {
 	Type    Type
 	Literal string
+	// Start is the position of the first character of the token.
+	Start Position
+	// End is the position just past the last character of the token.
+	End Position
+}
+
+// Span returns the source range covered by the token.
+func (t Token) Span() Span {
+	return Span{Start: t.Start, End: t.End}
+}
+
+// Position is a location in the source code.
+type Position struct {
+	// Filename is the name of the source file, if any.
+	Filename string
+	// Offset is the byte offset, starting at 0.
+	Offset int
+	// Line is the line number, starting at 1.
+	Line int
+	// Column is the column number, starting at 1.
+	Column int
+}
+
+// IsValid reports whether the position is set.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the form "file:line:column" or "line:column".
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if p.Filename != "" {
+		return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Span is a range of the source code.
+type Span struct {
+	Start Position
+	End   Position
 }
 
 // New returns a new instance of Token.
-func New(tokenType Type, ch byte) Token {
+func New(tokenType Type, ch rune) Token {
 	return Token{Type: tokenType, Literal: string(ch)}
 }
 
 var keywords = map[string]Type{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"const":    CONST,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"match":    MATCH,
+	"when":     WHEN,
+	"throw":    THROW,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
 }
 
 // LookupIdent returns the token type of the given identifier.
@@ -5,7 +5,7 @@ import "testing"
 func TestNew(t *testing.T) {
 	type inputs struct {
 		tokenType Type
-		ch        byte
+		ch        rune
 	}
 
 	tests := []struct {
@@ -109,3 +109,38 @@ func TestLookupIdent(t *testing.T) {
 		}
 	}
 }
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Position
+		want  string
+	}{
+		{
+			name:  "Without file name",
+			input: Position{Offset: 4, Line: 2, Column: 3},
+			want:  "2:3",
+		},
+		{
+			name:  "With file name",
+			input: Position{Filename: "main.mk", Offset: 4, Line: 2, Column: 3},
+			want:  "main.mk:2:3",
+		},
+		{
+			name:  "Invalid",
+			input: Position{},
+			want:  "-",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.String(); got != tt.want {
+			t.Fatalf(
+				"name: %s - position wrong. expected=%q, got=%q",
+				tt.name,
+				tt.want,
+				got,
+			)
+		}
+	}
+}